@@ -0,0 +1,344 @@
+package laplace
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// tradingDaysPerYear annualizes daily series
+const tradingDaysPerYear = 252
+
+// SeriesPoint is a price at a point in time, the form price series are analyzed in.
+type SeriesPoint struct {
+	Time  time.Time
+	Price float64
+}
+
+// SeriesFromPrices returns the close prices of points, as returned by GetHistoricalPrices,
+// GetCustomHistoricalPrices or GetAggregateGraph.
+func SeriesFromPrices(points []PriceDataPoint) []SeriesPoint {
+	series := make([]SeriesPoint, len(points))
+	for i, point := range points {
+		series[i] = SeriesPoint{Time: point.Time(), Price: point.Close}
+	}
+	return series
+}
+
+// SeriesFromFundPrices returns the prices of a fund, as returned by GetHistoricalFundPrices,
+// ordered by date.
+func SeriesFromFundPrices(prices []FundHistoricalPrice) []SeriesPoint {
+	series := make([]SeriesPoint, len(prices))
+	for i, price := range prices {
+		series[i] = SeriesPoint{Time: price.Date, Price: price.Price}
+	}
+	slices.SortStableFunc(series, func(a, b SeriesPoint) int { return a.Time.Compare(b.Time) })
+	return series
+}
+
+// AnalyticsOptions configures return and risk analytics.
+type AnalyticsOptions struct {
+	// PeriodsPerYear is the number of returns in a year, used to annualize volatility and the
+	// ratios. It is inferred from the spacing of the prices when 0: 252 for daily prices, 52
+	// for weekly and 12 for monthly ones. It must be set for intraday prices.
+	PeriodsPerYear float64
+	// RiskFreeRate is the annual risk-free rate the Sharpe and Sortino ratios and alpha are
+	// measured against, as a fraction
+	RiskFreeRate float64
+	// Benchmark is the series beta and alpha are measured against
+	Benchmark []SeriesPoint
+	// Location, when set, matches benchmark prices by calendar date in that time zone instead
+	// of by exact time, for series from different sources such as a fund against an index
+	Location *time.Location
+}
+
+// RiskMetrics are the return and risk statistics of a price series.
+type RiskMetrics struct {
+	From time.Time
+	To   time.Time
+	// Returns is the number of returns between consecutive prices
+	Returns int
+	// TotalReturn is the return from the first to the last price, as a fraction
+	TotalReturn float64
+	// AnnualizedReturn is TotalReturn compounded to a year of calendar time
+	AnnualizedReturn float64
+	// Volatility is the annualized standard deviation of the returns
+	Volatility float64
+	// MaxDrawdown is the largest fall from a peak, as a positive fraction
+	MaxDrawdown float64
+	// MaxDrawdownPeak and MaxDrawdownTrough bound the largest fall, and MaxDrawdownRecovery is
+	// when the price got back to the peak, zero if it has not
+	MaxDrawdownPeak     time.Time
+	MaxDrawdownTrough   time.Time
+	MaxDrawdownRecovery time.Time
+	// MaxDrawdownDuration is the time from the peak to the recovery, or to the last price if
+	// the price has not recovered
+	MaxDrawdownDuration time.Duration
+	// Sharpe is the annualized mean excess return over its standard deviation
+	Sharpe float64
+	// Sortino is the annualized mean excess return over the downside deviation
+	Sortino float64
+	// Beta and Alpha are the slope and the annualized intercept of the excess returns regressed
+	// on the benchmark's, 0 without a benchmark. BenchmarkReturns is the number of returns
+	// they were fitted on, over the dates both series have.
+	Beta             float64
+	Alpha            float64
+	BenchmarkReturns int
+}
+
+// AnalyzeReturns computes the return and risk statistics of a price series ordered by time.
+// Ratios that are undefined, such as the Sharpe ratio of a series that never moved, are 0.
+func AnalyzeReturns(series []SeriesPoint, opts AnalyticsOptions) (*RiskMetrics, error) {
+	analyzer, err := newReturnAnalyzer(series, opts)
+	if err != nil {
+		return nil, err
+	}
+	metrics := analyzer.analyze(series)
+	return &metrics, nil
+}
+
+// RollingRiskMetrics computes the statistics of AnalyzeReturns over every window of window
+// returns, that is window+1 consecutive prices, in order. The statistics of each window are
+// dated by its last price.
+func RollingRiskMetrics(series []SeriesPoint, window int, opts AnalyticsOptions) ([]RiskMetrics, error) {
+	if window < 2 {
+		return nil, fmt.Errorf("rolling window needs at least 2 returns, got %d", window)
+	}
+	analyzer, err := newReturnAnalyzer(series, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var rolling []RiskMetrics
+	for end := window + 1; end <= len(series); end++ {
+		rolling = append(rolling, analyzer.analyze(series[end-window-1:end]))
+	}
+	return rolling, nil
+}
+
+// PeriodReturn is the return over a calendar period.
+type PeriodReturn struct {
+	// Start is local midnight of the first day of the period
+	Start time.Time
+	// Return is the change from the last price of the previous period, or from the first price
+	// for the first period, to the last price of the period
+	Return float64
+}
+
+// PeriodReturns computes the return of every calendar week, month, quarter or year of a price
+// series ordered by time, with periods in the given time zone (UTC if nil) as ResampleCalendar
+// has them.
+func PeriodReturns(series []SeriesPoint, period CalendarPeriod, location *time.Location) ([]PeriodReturn, error) {
+	if err := checkSeries(series, 1); err != nil {
+		return nil, err
+	}
+	if location == nil {
+		location = time.UTC
+	}
+
+	points := make([]PriceDataPoint, len(series))
+	for i, point := range series {
+		points[i] = PriceDataPoint{Date: point.Time.UnixMilli(), Open: point.Price, High: point.Price, Low: point.Price, Close: point.Price}
+	}
+	bars, err := ResampleCalendar(points, period, location)
+	if err != nil {
+		return nil, err
+	}
+
+	returns := make([]PeriodReturn, len(bars))
+	base := bars[0].Open
+	for i, bar := range bars {
+		returns[i] = PeriodReturn{Start: bar.Time().In(location), Return: bar.Close/base - 1}
+		base = bar.Close
+	}
+	return returns, nil
+}
+
+// returnAnalyzer holds what the analyses of the windows of a series share
+type returnAnalyzer struct {
+	opts      AnalyticsOptions
+	riskFree  float64
+	benchmark map[string]float64
+}
+
+func newReturnAnalyzer(series []SeriesPoint, opts AnalyticsOptions) (*returnAnalyzer, error) {
+	if err := checkSeries(series, 2); err != nil {
+		return nil, err
+	}
+	if opts.PeriodsPerYear < 0 {
+		return nil, fmt.Errorf("invalid periods per year %g", opts.PeriodsPerYear)
+	}
+	if opts.PeriodsPerYear == 0 {
+		periods, err := inferPeriodsPerYear(series)
+		if err != nil {
+			return nil, err
+		}
+		opts.PeriodsPerYear = periods
+	}
+
+	analyzer := &returnAnalyzer{
+		opts:     opts,
+		riskFree: math.Pow(1+opts.RiskFreeRate, 1/opts.PeriodsPerYear) - 1,
+	}
+	if len(opts.Benchmark) > 0 {
+		if err := checkSeries(opts.Benchmark, 2); err != nil {
+			return nil, fmt.Errorf("invalid benchmark: %w", err)
+		}
+		analyzer.benchmark = make(map[string]float64, len(opts.Benchmark))
+		for _, point := range opts.Benchmark {
+			analyzer.benchmark[analyzer.key(point.Time)] = point.Price
+		}
+	}
+	return analyzer, nil
+}
+
+// key identifies the time benchmark prices are matched on
+func (a *returnAnalyzer) key(t time.Time) string {
+	if a.opts.Location != nil {
+		return t.In(a.opts.Location).Format(time.DateOnly)
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func (a *returnAnalyzer) analyze(series []SeriesPoint) RiskMetrics {
+	first, last := series[0], series[len(series)-1]
+	metrics := RiskMetrics{
+		From:        first.Time,
+		To:          last.Time,
+		Returns:     len(series) - 1,
+		TotalReturn: last.Price/first.Price - 1,
+	}
+	if years := last.Time.Sub(first.Time).Hours() / 24 / 365.25; years > 0 {
+		metrics.AnnualizedReturn = math.Pow(last.Price/first.Price, 1/years) - 1
+	}
+
+	returns := make([]float64, len(series)-1)
+	for i := range returns {
+		returns[i] = series[i+1].Price/series[i].Price - 1
+	}
+
+	annualize := math.Sqrt(a.opts.PeriodsPerYear)
+	mean, deviation := sampleMeanStdDev(returns)
+	metrics.Volatility = deviation * annualize
+	if deviation > 0 {
+		metrics.Sharpe = (mean - a.riskFree) / deviation * annualize
+	}
+
+	var downside float64
+	for _, r := range returns {
+		if r < a.riskFree {
+			downside += (r - a.riskFree) * (r - a.riskFree)
+		}
+	}
+	if downside > 0 {
+		metrics.Sortino = (mean - a.riskFree) / math.Sqrt(downside/float64(len(returns))) * annualize
+	}
+
+	a.drawdown(series, &metrics)
+	if a.benchmark != nil {
+		a.regress(series, &metrics)
+	}
+	return metrics
+}
+
+// drawdown finds the largest fall from a peak and its recovery in a single pass
+func (a *returnAnalyzer) drawdown(series []SeriesPoint, metrics *RiskMetrics) {
+	peak := series[0]
+	// recovering is set while the largest fall so far started at peak and has not recovered
+	recovering := false
+	for _, point := range series {
+		if point.Price >= peak.Price {
+			if recovering {
+				metrics.MaxDrawdownRecovery = point.Time
+				metrics.MaxDrawdownDuration = point.Time.Sub(peak.Time)
+				recovering = false
+			}
+			peak = point
+			continue
+		}
+		if fall := 1 - point.Price/peak.Price; fall > metrics.MaxDrawdown {
+			metrics.MaxDrawdown = fall
+			metrics.MaxDrawdownPeak = peak.Time
+			metrics.MaxDrawdownTrough = point.Time
+			metrics.MaxDrawdownRecovery = time.Time{}
+			recovering = true
+		}
+	}
+	if recovering {
+		metrics.MaxDrawdownDuration = series[len(series)-1].Time.Sub(peak.Time)
+	}
+}
+
+// regress fits the excess returns of series to those of the benchmark over the times both have
+func (a *returnAnalyzer) regress(series []SeriesPoint, metrics *RiskMetrics) {
+	var stock, benchmark []float64
+	var previous SeriesPoint
+	var previousBenchmark float64
+	matched := false
+	for _, point := range series {
+		price, ok := a.benchmark[a.key(point.Time)]
+		if !ok {
+			continue
+		}
+		if matched {
+			stock = append(stock, point.Price/previous.Price-1-a.riskFree)
+			benchmark = append(benchmark, price/previousBenchmark-1-a.riskFree)
+		}
+		previous, previousBenchmark, matched = point, price, true
+	}
+
+	metrics.BenchmarkReturns = len(stock)
+	if len(stock) < 2 {
+		return
+	}
+	alpha, beta := fitMarketModel(stock, benchmark)
+	metrics.Alpha, metrics.Beta = alpha*a.opts.PeriodsPerYear, beta
+}
+
+// checkSeries makes sure a series has enough positive prices in time order
+func checkSeries(series []SeriesPoint, minimum int) error {
+	if len(series) < minimum {
+		return fmt.Errorf("series needs at least %d prices, got %d", minimum, len(series))
+	}
+	for i, point := range series {
+		if point.Price <= 0 {
+			return fmt.Errorf("price at %s is not positive: %g", point.Time.Format(time.RFC3339), point.Price)
+		}
+		if i > 0 && !point.Time.After(series[i-1].Time) {
+			return fmt.Errorf("prices are not ordered by time at %s", point.Time.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// inferPeriodsPerYear infers the number of returns per year from the median spacing of prices
+func inferPeriodsPerYear(series []SeriesPoint) (float64, error) {
+	gaps := make([]float64, len(series)-1)
+	for i := range gaps {
+		gaps[i] = series[i+1].Time.Sub(series[i].Time).Hours() / 24
+	}
+
+	switch gap := median(gaps); {
+	case gap < 1:
+		return 0, fmt.Errorf("periods per year must be set for intraday prices")
+	case gap <= 4:
+		// Weekends and holidays widen some gaps of daily prices
+		return tradingDaysPerYear, nil
+	case gap <= 10:
+		return 52, nil
+	case gap <= 45:
+		return 12, nil
+	default:
+		return 365.25 / gap, nil
+	}
+}
+
+// sampleMeanStdDev returns the mean and the sample standard deviation of values
+func sampleMeanStdDev(values []float64) (float64, float64) {
+	mean, deviation := meanStdDev(values)
+	if len(values) < 2 {
+		return mean, 0
+	}
+	return mean, deviation * math.Sqrt(float64(len(values))/float64(len(values)-1))
+}
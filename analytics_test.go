@@ -0,0 +1,154 @@
+package laplace
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// dailySeries dates prices on consecutive days from 2025-03-03
+func dailySeries(prices ...float64) []SeriesPoint {
+	series := make([]SeriesPoint, len(prices))
+	for i, price := range prices {
+		series[i] = SeriesPoint{Time: time.Date(2025, 3, 3+i, 0, 0, 0, 0, time.UTC), Price: price}
+	}
+	return series
+}
+
+func TestAnalyzeReturns(t *testing.T) {
+	series := dailySeries(100, 110, 99, 121)
+
+	metrics, err := AnalyzeReturns(series, AnalyticsOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 3, metrics.Returns)
+	require.InDelta(t, 0.21, metrics.TotalReturn, 1e-12)
+	require.InDelta(t, math.Pow(1.21, 365.25/3)-1, metrics.AnnualizedReturn, 1e-6*metrics.AnnualizedReturn)
+	require.InDelta(t, 2.582275769190454, metrics.Volatility, 1e-9)
+	require.InDelta(t, 7.228765761341866, metrics.Sharpe, 1e-9)
+	require.InDelta(t, 20.36700308869265, metrics.Sortino, 1e-9)
+
+	require.InDelta(t, 0.1, metrics.MaxDrawdown, 1e-12)
+	require.Equal(t, series[1].Time, metrics.MaxDrawdownPeak)
+	require.Equal(t, series[2].Time, metrics.MaxDrawdownTrough)
+	require.Equal(t, series[3].Time, metrics.MaxDrawdownRecovery)
+	require.Equal(t, 48*time.Hour, metrics.MaxDrawdownDuration)
+
+	require.Zero(t, metrics.Beta)
+	require.Zero(t, metrics.BenchmarkReturns)
+}
+
+func TestAnalyzeReturnsUnrecoveredDrawdown(t *testing.T) {
+	series := dailySeries(100, 80, 90, 70, 95)
+
+	metrics, err := AnalyzeReturns(series, AnalyticsOptions{})
+	require.NoError(t, err)
+	require.InDelta(t, 0.3, metrics.MaxDrawdown, 1e-12)
+	require.Equal(t, series[0].Time, metrics.MaxDrawdownPeak)
+	require.Equal(t, series[3].Time, metrics.MaxDrawdownTrough)
+	require.True(t, metrics.MaxDrawdownRecovery.IsZero())
+	require.Equal(t, 96*time.Hour, metrics.MaxDrawdownDuration)
+}
+
+func TestAnalyzeReturnsLaterDrawdown(t *testing.T) {
+	series := dailySeries(100, 90, 100, 60, 120, 110)
+
+	metrics, err := AnalyzeReturns(series, AnalyticsOptions{})
+	require.NoError(t, err)
+	require.InDelta(t, 0.4, metrics.MaxDrawdown, 1e-12)
+	require.Equal(t, series[2].Time, metrics.MaxDrawdownPeak)
+	require.Equal(t, series[3].Time, metrics.MaxDrawdownTrough)
+	require.Equal(t, series[4].Time, metrics.MaxDrawdownRecovery)
+	require.Equal(t, 48*time.Hour, metrics.MaxDrawdownDuration)
+}
+
+func TestAnalyzeReturnsBenchmark(t *testing.T) {
+	benchmarkReturns := []float64{0.01, -0.02, 0.03, 0.01, -0.01}
+	benchmark := []float64{100}
+	stock := []float64{50}
+	for _, r := range benchmarkReturns {
+		benchmark = append(benchmark, benchmark[len(benchmark)-1]*(1+r))
+		stock = append(stock, stock[len(stock)-1]*(1+2*r))
+	}
+
+	metrics, err := AnalyzeReturns(dailySeries(stock...), AnalyticsOptions{Benchmark: dailySeries(benchmark...)})
+	require.NoError(t, err)
+	require.Equal(t, 5, metrics.BenchmarkReturns)
+	require.InDelta(t, 2, metrics.Beta, 1e-9)
+	require.InDelta(t, 0, metrics.Alpha, 1e-9)
+
+	// A fund priced at midnight matched against index closes by date, with a missing day
+	location := RegionTr.Location()
+	fund := make([]SeriesPoint, len(stock))
+	index := make([]SeriesPoint, 0, len(benchmark))
+	for i := range stock {
+		day := time.Date(2025, 3, 3+i, 0, 0, 0, 0, location)
+		fund[i] = SeriesPoint{Time: day, Price: stock[i]}
+		if i != 2 {
+			index = append(index, SeriesPoint{Time: day.Add(18 * time.Hour), Price: benchmark[i]})
+		}
+	}
+
+	metrics, err = AnalyzeReturns(fund, AnalyticsOptions{Benchmark: index, Location: location})
+	require.NoError(t, err)
+	require.Equal(t, 4, metrics.BenchmarkReturns)
+	require.Greater(t, metrics.Beta, 1.9)
+}
+
+func TestRollingRiskMetrics(t *testing.T) {
+	series := dailySeries(100, 110, 99, 121)
+
+	rolling, err := RollingRiskMetrics(series, 2, AnalyticsOptions{})
+	require.NoError(t, err)
+	require.Len(t, rolling, 2)
+	require.Equal(t, series[2].Time, rolling[0].To)
+	require.InDelta(t, -0.01, rolling[0].TotalReturn, 1e-12)
+	require.Equal(t, series[3].Time, rolling[1].To)
+	require.InDelta(t, 0.1, rolling[1].MaxDrawdown, 1e-12)
+
+	_, err = RollingRiskMetrics(series, 1, AnalyticsOptions{})
+	require.Error(t, err)
+}
+
+func TestPeriodReturns(t *testing.T) {
+	at := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	series := []SeriesPoint{
+		{at(1, 30), 90},
+		{at(1, 31), 100},
+		{at(2, 3), 105},
+		{at(2, 28), 110},
+		{at(3, 3), 99},
+	}
+
+	returns, err := PeriodReturns(series, CalendarMonth, nil)
+	require.NoError(t, err)
+	require.Len(t, returns, 3)
+	require.Equal(t, at(1, 1), returns[0].Start)
+	require.InDelta(t, 100.0/90-1, returns[0].Return, 1e-12)
+	require.InDelta(t, 0.1, returns[1].Return, 1e-12)
+	require.InDelta(t, -0.1, returns[2].Return, 1e-12)
+}
+
+func TestAnalyzeReturnsErrors(t *testing.T) {
+	_, err := AnalyzeReturns(dailySeries(100), AnalyticsOptions{})
+	require.ErrorContains(t, err, "at least 2 prices")
+
+	_, err = AnalyzeReturns(dailySeries(100, 0), AnalyticsOptions{})
+	require.ErrorContains(t, err, "not positive")
+
+	unordered := dailySeries(100, 101)
+	unordered[0], unordered[1] = unordered[1], unordered[0]
+	_, err = AnalyzeReturns(unordered, AnalyticsOptions{})
+	require.ErrorContains(t, err, "not ordered")
+
+	start := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+	intraday := []SeriesPoint{{start, 100}, {start.Add(time.Hour), 101}, {start.Add(2 * time.Hour), 102}}
+	_, err = AnalyzeReturns(intraday, AnalyticsOptions{})
+	require.ErrorContains(t, err, "intraday")
+
+	_, err = AnalyzeReturns(intraday, AnalyticsOptions{PeriodsPerYear: 252 * 8})
+	require.NoError(t, err)
+}
@@ -0,0 +1,148 @@
+// Package chart renders price charts of laplace.PriceDataPoint series to SVG and PNG without
+// calling the API, so that charts can be made offline and in bulk.
+//
+// Candlestick, OHLC, line and area charts are supported, with an optional volume pane, overlays
+// such as the moving averages of the indicators package, light and dark themes, and axes
+// formatted for Turkish or English. Rendering functions are safe for concurrent use.
+package chart
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"slices"
+	"time"
+
+	laplace "github.com/Laplace-Analytics/laplace-api-golang"
+)
+
+// Type is the way bars are drawn.
+type Type string
+
+const (
+	TypeCandlestick Type = "candlestick"
+	TypeOHLC        Type = "ohlc"
+	TypeLine        Type = "line"
+	TypeArea        Type = "area"
+)
+
+const (
+	defaultWidth  = 960
+	defaultHeight = 540
+	minWidth      = 200
+	minHeight     = 120
+)
+
+// Overlay is a series drawn over the prices, such as a moving average.
+type Overlay struct {
+	Label string
+	// Values holds one value per bar, NaN where there is none, as the batch functions of the
+	// indicators package return them
+	Values []float64
+	// Color is the line color; the theme's overlay colors are used in turn when it is zero
+	Color color.NRGBA
+}
+
+// Options configures a chart.
+type Options struct {
+	// Width and Height are the size of the chart in pixels, 960 by 540 by default
+	Width  int
+	Height int
+	// Type is TypeCandlestick by default
+	Type  Type
+	Title string
+	// Volume adds a pane with the volume of every bar below the prices
+	Volume   bool
+	Overlays []Overlay
+	// Theme is LightTheme by default
+	Theme *Theme
+	// Locale formats the axes: LocaleTr writes 1.234,56 and Turkish month names, anything
+	// else 1,234.56 and English ones
+	Locale laplace.Locale
+	// Location is the time zone of the time axis, UTC by default; Region.Location gives that
+	// of an exchange
+	Location *time.Location
+	// TextScale enlarges the text, 1 by default
+	TextScale int
+}
+
+// withDefaults validates the options against the bars and fills in the defaults
+func (o Options) withDefaults(points []laplace.PriceDataPoint) (Options, error) {
+	if len(points) == 0 {
+		return o, errors.New("no bars to chart")
+	}
+	if !slices.IsSortedFunc(points, func(a, b laplace.PriceDataPoint) int { return a.Time().Compare(b.Time()) }) {
+		return o, errors.New("bars are not ordered by date")
+	}
+
+	if o.Width == 0 {
+		o.Width = defaultWidth
+	}
+	if o.Height == 0 {
+		o.Height = defaultHeight
+	}
+	if o.Width < minWidth || o.Height < minHeight {
+		return o, fmt.Errorf("chart size %dx%d is below the minimum of %dx%d", o.Width, o.Height, minWidth, minHeight)
+	}
+
+	switch o.Type {
+	case "":
+		o.Type = TypeCandlestick
+	case TypeCandlestick, TypeOHLC, TypeLine, TypeArea:
+	default:
+		return o, fmt.Errorf("invalid chart type %q", o.Type)
+	}
+
+	for _, overlay := range o.Overlays {
+		if len(overlay.Values) != len(points) {
+			return o, fmt.Errorf("overlay %q has %d values for %d bars", overlay.Label, len(overlay.Values), len(points))
+		}
+	}
+
+	if o.Theme == nil {
+		o.Theme = &LightTheme
+	}
+	if o.Location == nil {
+		o.Location = time.UTC
+	}
+	if o.TextScale <= 0 {
+		o.TextScale = 1
+	}
+	return o, nil
+}
+
+// RenderSVG draws a chart of points as an SVG document.
+func RenderSVG(w io.Writer, points []laplace.PriceDataPoint, opts Options) error {
+	opts, err := opts.withDefaults(points)
+	if err != nil {
+		return err
+	}
+
+	svg := newSVG(opts.Width, opts.Height)
+	draw(svg, points, opts)
+	return svg.writeTo(w)
+}
+
+// RenderImage draws a chart of points as an image.
+func RenderImage(points []laplace.PriceDataPoint, opts Options) (*image.NRGBA, error) {
+	opts, err := opts.withDefaults(points)
+	if err != nil {
+		return nil, err
+	}
+
+	raster := newRaster(opts.Width, opts.Height)
+	draw(raster, points, opts)
+	return raster.img, nil
+}
+
+// RenderPNG draws a chart of points as a PNG image.
+func RenderPNG(w io.Writer, points []laplace.PriceDataPoint, opts Options) error {
+	img, err := RenderImage(points, opts)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
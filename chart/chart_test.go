@@ -0,0 +1,156 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	laplace "github.com/Laplace-Analytics/laplace-api-golang"
+)
+
+// testBars returns n daily bars alternating between rising and falling
+func testBars(n int) []laplace.PriceDataPoint {
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, laplace.RegionTr.Location())
+	points := make([]laplace.PriceDataPoint, n)
+	for i := range points {
+		open := 100 + float64(i)
+		closing := open + 2
+		if i%2 == 1 {
+			closing = open - 2
+		}
+		points[i] = laplace.PriceDataPoint{
+			Date:   start.AddDate(0, 0, i).UnixMilli(),
+			Open:   open,
+			High:   math.Max(open, closing) + 1,
+			Low:    math.Min(open, closing) - 1,
+			Close:  closing,
+			Volume: float64(1000 * (i + 1)),
+		}
+	}
+	return points
+}
+
+func TestRenderSVG(t *testing.T) {
+	points := testBars(30)
+	average := make([]float64, len(points))
+	for i := range average {
+		average[i] = math.NaN()
+		if i >= 4 {
+			average[i] = points[i].Close
+		}
+	}
+
+	for _, chartType := range []Type{TypeCandlestick, TypeOHLC, TypeLine, TypeArea} {
+		var buf bytes.Buffer
+		err := RenderSVG(&buf, points, Options{
+			Type:     chartType,
+			Title:    "THYAO <Günlük>",
+			Volume:   true,
+			Overlays: []Overlay{{Label: "SMA 5", Values: average}},
+			Locale:   laplace.LocaleTr,
+			Location: laplace.RegionTr.Location(),
+		})
+		require.NoError(t, err, chartType)
+
+		// The document is well-formed
+		decoder := xml.NewDecoder(&buf)
+		elements := make(map[string]int)
+		var texts []string
+		for {
+			token, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err, chartType)
+			switch token := token.(type) {
+			case xml.StartElement:
+				elements[token.Name.Local]++
+			case xml.CharData:
+				if text := strings.TrimSpace(string(token)); text != "" {
+					texts = append(texts, text)
+				}
+			}
+		}
+
+		require.Equal(t, 1, elements["svg"])
+		require.Contains(t, texts, "THYAO <Günlük>")
+		require.Contains(t, texts, "SMA 5")
+		require.Contains(t, texts, "6 Oca")
+		require.Contains(t, texts, "127,00")
+		switch chartType {
+		case TypeCandlestick:
+			require.Greater(t, elements["rect"], 2*len(points))
+		case TypeArea:
+			require.Equal(t, 1, elements["polygon"])
+		}
+		// One line for the closes of line and area charts, one for the overlay
+		if chartType == TypeLine || chartType == TypeArea {
+			require.Equal(t, 2, elements["polyline"])
+		} else {
+			require.Equal(t, 1, elements["polyline"])
+		}
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	points := testBars(20)
+
+	var buf bytes.Buffer
+	require.NoError(t, RenderPNG(&buf, points, Options{Width: 400, Height: 300, Theme: &DarkTheme}))
+
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+	require.Equal(t, 400, img.Bounds().Dx())
+	require.Equal(t, 300, img.Bounds().Dy())
+
+	// The background and the colors of both rising and falling candles are drawn
+	colors := make(map[[3]uint32]bool)
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 400; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			colors[[3]uint32{r >> 8, g >> 8, b >> 8}] = true
+		}
+	}
+	for _, c := range []struct{ R, G, B uint8 }{
+		{DarkTheme.Background.R, DarkTheme.Background.G, DarkTheme.Background.B},
+		{DarkTheme.Up.R, DarkTheme.Up.G, DarkTheme.Up.B},
+		{DarkTheme.Down.R, DarkTheme.Down.G, DarkTheme.Down.B},
+	} {
+		require.True(t, colors[[3]uint32{uint32(c.R), uint32(c.G), uint32(c.B)}], "color %v", c)
+	}
+}
+
+func TestRenderSingleBar(t *testing.T) {
+	points := []laplace.PriceDataPoint{{Date: 1, Open: 5, High: 5, Low: 5, Close: 5}}
+	for _, chartType := range []Type{TypeCandlestick, TypeLine, TypeArea} {
+		_, err := RenderImage(points, Options{Type: chartType, Volume: true})
+		require.NoError(t, err, chartType)
+	}
+}
+
+func TestRenderOptionsErrors(t *testing.T) {
+	points := testBars(3)
+
+	_, err := RenderImage(nil, Options{})
+	require.ErrorContains(t, err, "no bars")
+
+	_, err = RenderImage(points, Options{Type: "heikin-ashi"})
+	require.ErrorContains(t, err, "invalid chart type")
+
+	_, err = RenderImage(points, Options{Width: 100, Height: 100})
+	require.ErrorContains(t, err, "below the minimum")
+
+	_, err = RenderImage(points, Options{Overlays: []Overlay{{Label: "SMA", Values: []float64{1}}}})
+	require.ErrorContains(t, err, "has 1 values for 3 bars")
+
+	points[0], points[1] = points[1], points[0]
+	_, err = RenderImage(points, Options{})
+	require.ErrorContains(t, err, "not ordered")
+}
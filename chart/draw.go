@@ -0,0 +1,254 @@
+package chart
+
+import (
+	"image/color"
+	"math"
+
+	laplace "github.com/Laplace-Analytics/laplace-api-golang"
+)
+
+// volumeShare is the share of the plot height the volume pane takes
+const volumeShare = 0.22
+
+type point struct {
+	x, y float64
+}
+
+type anchor int
+
+const (
+	anchorStart anchor = iota
+	anchorMiddle
+	anchorEnd
+)
+
+// canvas is what charts are drawn on, in pixels from the top left corner. Text is vertically
+// centered on y.
+type canvas interface {
+	fillRect(x, y, width, height float64, c color.NRGBA)
+	line(x1, y1, x2, y2 float64, c color.NRGBA, width float64)
+	polyline(points []point, c color.NRGBA, width float64)
+	polygon(points []point, c color.NRGBA)
+	text(x, y float64, s string, c color.NRGBA, scale int, anchor anchor)
+}
+
+// scale maps values to a vertical range of pixels
+type scale struct {
+	low, high   float64
+	top, bottom float64
+}
+
+func (s scale) y(value float64) float64 {
+	return s.bottom - (value-s.low)/(s.high-s.low)*(s.bottom-s.top)
+}
+
+// draw lays out and draws a chart with validated options
+func draw(cv canvas, points []laplace.PriceDataPoint, opts Options) {
+	theme := opts.Theme
+	textScale := opts.TextScale
+	pad := 10 * float64(textScale)
+	lineHeight := float64(glyphHeight * textScale)
+	width, height := float64(opts.Width), float64(opts.Height)
+
+	cv.fillRect(0, 0, width, height, theme.Background)
+
+	top := pad
+	if opts.Title != "" {
+		cv.text(pad, top+lineHeight, opts.Title, theme.Text, 2*textScale, anchorStart)
+		top += 2*lineHeight + pad
+	}
+	bottom := height - lineHeight - 1.5*pad
+
+	prices := scale{top: top, bottom: bottom}
+	var volumes scale
+	if opts.Volume {
+		volumes = scale{top: bottom - (bottom-top)*volumeShare, bottom: bottom}
+		prices.bottom = volumes.top - pad
+	}
+	prices.low, prices.high = priceRange(points, opts)
+
+	ticks, step := niceTicks(prices.low, prices.high, max(2, int((prices.bottom-prices.top)/(5*lineHeight))))
+	decimals := stepDecimals(step)
+	labels := make([]string, len(ticks))
+	axisWidth := 0
+	for i, tick := range ticks {
+		labels[i] = formatNumber(tick, decimals, opts.Locale)
+		axisWidth = max(axisWidth, textWidth(labels[i], textScale))
+	}
+	last := points[len(points)-1]
+	lastLabel := formatNumber(last.Close, max(decimals, 2), opts.Locale)
+	axisWidth = max(axisWidth, textWidth(lastLabel, textScale)+int(pad/2))
+
+	left, right := pad, width-pad-float64(axisWidth)-pad/2
+	slot := (right - left) / float64(len(points))
+	x := func(i int) float64 { return left + (float64(i)+0.5)*slot }
+
+	// Grid and axes
+	for i, tick := range ticks {
+		y := prices.y(tick)
+		cv.line(left, y, right, y, theme.Grid, 1)
+		cv.text(right+pad/2, y, labels[i], theme.Text, textScale, anchorStart)
+	}
+
+	formatTime := timeFormatter(points, opts.Location, opts.Locale)
+	labelWidth := float64(max(textWidth(formatTime(points[0].Time()), textScale), textWidth(formatTime(last.Time()), textScale)))
+	every := max(1, int(math.Ceil((labelWidth+3*pad)/slot)))
+	for i := 0; i < len(points); i += every {
+		cv.line(x(i), prices.top, x(i), bottom, theme.Grid, 1)
+		// Labels at the ends are kept from running off the plot
+		label := formatTime(points[i].Time())
+		half := float64(textWidth(label, textScale)) / 2
+		cv.text(math.Min(math.Max(x(i), half), right-half), bottom+pad/2+lineHeight/2, label, theme.Text, textScale, anchorMiddle)
+	}
+
+	cv.line(left, bottom, right, bottom, theme.Axis, 1)
+	cv.line(right, prices.top, right, bottom, theme.Axis, 1)
+
+	if opts.Volume {
+		drawVolume(cv, points, opts, volumes, x, slot, right, pad)
+	}
+
+	drawBars(cv, points, opts, prices, x, slot)
+
+	overlayColor := func(k int) color.NRGBA {
+		if c := opts.Overlays[k].Color; c.A != 0 || len(theme.Overlays) == 0 {
+			return c
+		}
+		return theme.Overlays[k%len(theme.Overlays)]
+	}
+	legendX := left + pad/2
+	for k, overlay := range opts.Overlays {
+		c := overlayColor(k)
+		var run []point
+		for i, value := range overlay.Values {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				if len(run) > 1 {
+					cv.polyline(run, c, 1.5)
+				}
+				run = run[:0]
+				continue
+			}
+			run = append(run, point{x(i), prices.y(value)})
+		}
+		if len(run) > 1 {
+			cv.polyline(run, c, 1.5)
+		}
+
+		if overlay.Label != "" {
+			legendY := prices.top + lineHeight/2 + pad/2
+			cv.fillRect(legendX, legendY-lineHeight/4, lineHeight, lineHeight/2, c)
+			cv.text(legendX+lineHeight+pad/3, legendY, overlay.Label, theme.Text, textScale, anchorStart)
+			legendX += lineHeight + pad/3 + float64(textWidth(overlay.Label, textScale)) + pad
+		}
+	}
+
+	// The last close is tagged on the price axis
+	tag := theme.Line
+	if opts.Type == TypeCandlestick || opts.Type == TypeOHLC {
+		tag = barColor(last, theme)
+	}
+	y := prices.y(last.Close)
+	cv.fillRect(right, y-lineHeight/2-2, float64(axisWidth)+pad, lineHeight+4, tag)
+	cv.text(right+pad/2, y, lastLabel, theme.Background, textScale, anchorStart)
+}
+
+// priceRange returns the range the price pane spans, with some room above and below
+func priceRange(points []laplace.PriceDataPoint, opts Options) (float64, float64) {
+	low, high := math.Inf(1), math.Inf(-1)
+	include := func(value float64) {
+		if !math.IsNaN(value) && !math.IsInf(value, 0) {
+			low, high = math.Min(low, value), math.Max(high, value)
+		}
+	}
+
+	for _, point := range points {
+		if opts.Type == TypeLine || opts.Type == TypeArea {
+			include(point.Close)
+		} else {
+			include(point.Low)
+			include(point.High)
+		}
+	}
+	for _, overlay := range opts.Overlays {
+		for _, value := range overlay.Values {
+			include(value)
+		}
+	}
+
+	if high == low {
+		spread := math.Max(math.Abs(low)*0.01, 1e-6)
+		return low - spread, high + spread
+	}
+	margin := (high - low) * 0.05
+	return low - margin, high + margin
+}
+
+func barColor(point laplace.PriceDataPoint, theme *Theme) color.NRGBA {
+	if point.Close < point.Open {
+		return theme.Down
+	}
+	return theme.Up
+}
+
+func drawBars(cv canvas, points []laplace.PriceDataPoint, opts Options, prices scale, x func(int) float64, slot float64) {
+	theme := opts.Theme
+	body := math.Max(1, slot*0.7)
+
+	switch opts.Type {
+	case TypeCandlestick:
+		for i, point := range points {
+			c := barColor(point, theme)
+			cv.line(x(i), prices.y(point.High), x(i), prices.y(point.Low), c, 1)
+			top := prices.y(math.Max(point.Open, point.Close))
+			cv.fillRect(x(i)-body/2, top, body, math.Max(1, prices.y(math.Min(point.Open, point.Close))-top), c)
+		}
+
+	case TypeOHLC:
+		for i, point := range points {
+			c := barColor(point, theme)
+			cv.line(x(i), prices.y(point.High), x(i), prices.y(point.Low), c, 1)
+			cv.line(x(i)-body/2, prices.y(point.Open), x(i), prices.y(point.Open), c, 1)
+			cv.line(x(i), prices.y(point.Close), x(i)+body/2, prices.y(point.Close), c, 1)
+		}
+
+	case TypeLine, TypeArea:
+		line := make([]point, len(points))
+		for i, p := range points {
+			line[i] = point{x(i), prices.y(p.Close)}
+		}
+		if opts.Type == TypeArea {
+			area := append([]point{{line[0].x, prices.bottom}}, line...)
+			area = append(area, point{line[len(line)-1].x, prices.bottom})
+			cv.polygon(area, theme.Area)
+		}
+		if len(line) == 1 {
+			cv.fillRect(line[0].x-1, line[0].y-1, 2, 2, theme.Line)
+			return
+		}
+		cv.polyline(line, theme.Line, 2)
+	}
+}
+
+func drawVolume(cv canvas, points []laplace.PriceDataPoint, opts Options, volumes scale, x func(int) float64, slot, right, pad float64) {
+	theme := opts.Theme
+	for _, point := range points {
+		volumes.high = math.Max(volumes.high, point.Volume)
+	}
+	if volumes.high == 0 {
+		volumes.high = 1
+	}
+
+	cv.line(x(0)-slot/2, volumes.top, right, volumes.top, theme.Grid, 1)
+	cv.text(right+pad/2, volumes.top, formatVolume(volumes.high, opts.Locale), theme.Text, opts.TextScale, anchorStart)
+
+	body := math.Max(1, slot*0.7)
+	for i, point := range points {
+		if point.Volume <= 0 {
+			continue
+		}
+		c := barColor(point, theme)
+		c.A = 0x66
+		y := volumes.y(point.Volume)
+		cv.fillRect(x(i)-body/2, y, body, volumes.bottom-y, c)
+	}
+}
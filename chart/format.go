@@ -0,0 +1,143 @@
+package chart
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	laplace "github.com/Laplace-Analytics/laplace-api-golang"
+)
+
+var (
+	monthsEn = [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+	monthsTr = [12]string{"Oca", "Şub", "Mar", "Nis", "May", "Haz", "Tem", "Ağu", "Eyl", "Eki", "Kas", "Ara"}
+)
+
+// niceTicks returns about target evenly spaced round values within [low, high], stepping by
+// 1, 2 or 5 times a power of ten, and the step
+func niceTicks(low, high float64, target int) ([]float64, float64) {
+	if high <= low || target < 1 {
+		return []float64{low}, 0
+	}
+
+	rough := (high - low) / float64(target)
+	magnitude := math.Pow(10, math.Floor(math.Log10(rough)))
+	var step float64
+	switch residual := rough / magnitude; {
+	case residual < 1.5:
+		step = magnitude
+	case residual < 3:
+		step = 2 * magnitude
+	case residual < 7:
+		step = 5 * magnitude
+	default:
+		step = 10 * magnitude
+	}
+
+	var ticks []float64
+	for tick := math.Ceil(low/step) * step; tick <= high+step*1e-9; tick += step {
+		// Snap to the step to keep floating point noise out of the labels
+		ticks = append(ticks, math.Round(tick/step)*step)
+	}
+	return ticks, step
+}
+
+// stepDecimals returns the number of decimals that tell ticks step apart
+func stepDecimals(step float64) int {
+	if step <= 0 || step >= 1 {
+		return 0
+	}
+	return int(math.Ceil(-math.Log10(step) - 1e-9))
+}
+
+// separators returns the thousands and decimal separators of a locale
+func separators(locale laplace.Locale) (string, string) {
+	if locale == laplace.LocaleTr {
+		return ".", ","
+	}
+	return ",", "."
+}
+
+// formatNumber formats value with decimals digits and the separators of locale
+func formatNumber(value float64, decimals int, locale laplace.Locale) string {
+	thousands, point := separators(locale)
+
+	digits := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	whole, fraction, _ := strings.Cut(digits, ".")
+
+	var b strings.Builder
+	if value < 0 && strings.Trim(digits, "0.") != "" {
+		b.WriteByte('-')
+	}
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(thousands)
+		}
+		b.WriteRune(digit)
+	}
+	if fraction != "" {
+		b.WriteString(point)
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// formatVolume formats a volume compactly: 1.5M in English, 1,5 Mn in Turkish
+func formatVolume(value float64, locale laplace.Locale) string {
+	units := []struct {
+		size   float64
+		en, tr string
+	}{{1e9, "B", " Mr"}, {1e6, "M", " Mn"}, {1e3, "K", " B"}}
+
+	for _, unit := range units {
+		if math.Abs(value) < unit.size {
+			continue
+		}
+		scaled := value / unit.size
+		decimals := 0
+		if math.Abs(scaled) < 10 {
+			decimals = 1
+		}
+		suffix := unit.en
+		if locale == laplace.LocaleTr {
+			suffix = unit.tr
+		}
+		return formatNumber(scaled, decimals, locale) + suffix
+	}
+	return formatNumber(value, 0, locale)
+}
+
+// timeFormatter returns how the time axis labels bars: by time of day for a single session, by
+// day and time for several, by day for up to about a year of daily bars and by month beyond
+func timeFormatter(points []laplace.PriceDataPoint, location *time.Location, locale laplace.Locale) func(time.Time) string {
+	months := monthsEn
+	if locale == laplace.LocaleTr {
+		months = monthsTr
+	}
+
+	first, last := points[0].Time(), points[len(points)-1].Time()
+	span := last.Sub(first)
+	gaps := make([]time.Duration, 0, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		gaps = append(gaps, points[i].Time().Sub(points[i-1].Time()))
+	}
+	slices.Sort(gaps)
+	intraday := len(gaps) > 0 && gaps[len(gaps)/2] < 24*time.Hour
+
+	return func(t time.Time) string {
+		t = t.In(location)
+		switch {
+		case intraday && span < 24*time.Hour:
+			return t.Format("15:04")
+		case intraday:
+			return fmt.Sprintf("%d %s %s", t.Day(), months[t.Month()-1], t.Format("15:04"))
+		case span <= 400*24*time.Hour:
+			return fmt.Sprintf("%d %s", t.Day(), months[t.Month()-1])
+		default:
+			return fmt.Sprintf("%s %d", months[t.Month()-1], t.Year())
+		}
+	}
+}
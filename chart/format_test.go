@@ -0,0 +1,63 @@
+package chart
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	laplace "github.com/Laplace-Analytics/laplace-api-golang"
+)
+
+func TestNiceTicks(t *testing.T) {
+	ticks, step := niceTicks(97.3, 124.6, 5)
+	require.Equal(t, 5.0, step)
+	require.Equal(t, []float64{100, 105, 110, 115, 120}, ticks)
+
+	ticks, step = niceTicks(0.12, 0.31, 4)
+	require.InDelta(t, 0.05, step, 1e-12)
+	require.Len(t, ticks, 4)
+	require.Equal(t, 2, stepDecimals(step))
+
+	require.Equal(t, 0, stepDecimals(5))
+	require.Equal(t, 1, stepDecimals(0.5))
+	require.Equal(t, 1, stepDecimals(0.2))
+}
+
+func TestFormatNumber(t *testing.T) {
+	require.Equal(t, "1,234,567.89", formatNumber(1234567.891, 2, laplace.LocaleEn))
+	require.Equal(t, "1.234.567,89", formatNumber(1234567.891, 2, laplace.LocaleTr))
+	require.Equal(t, "-12,5", formatNumber(-12.5, 1, laplace.LocaleTr))
+	require.Equal(t, "0", formatNumber(-0.0001, 0, laplace.LocaleEn))
+	require.Equal(t, "999", formatNumber(999, 0, laplace.LocaleEn))
+
+	require.Equal(t, "1.5M", formatVolume(1_500_000, laplace.LocaleEn))
+	require.Equal(t, "1,5 Mn", formatVolume(1_500_000, laplace.LocaleTr))
+	require.Equal(t, "25 Mr", formatVolume(25_000_000_000, laplace.LocaleTr))
+	require.Equal(t, "12K", formatVolume(12_000, laplace.LocaleEn))
+	require.Equal(t, "850", formatVolume(850, laplace.LocaleEn))
+}
+
+func TestTimeFormatter(t *testing.T) {
+	location := laplace.RegionTr.Location()
+	bars := func(start time.Time, step time.Duration, n int) []laplace.PriceDataPoint {
+		points := make([]laplace.PriceDataPoint, n)
+		for i := range points {
+			points[i].Date = start.Add(time.Duration(i) * step).UnixMilli()
+		}
+		return points
+	}
+	at := time.Date(2025, 2, 3, 10, 30, 0, 0, location)
+
+	format := timeFormatter(bars(at, time.Hour, 8), location, laplace.LocaleTr)
+	require.Equal(t, "10:30", format(at))
+
+	format = timeFormatter(bars(at, time.Hour, 30), location, laplace.LocaleTr)
+	require.Equal(t, "3 Şub 10:30", format(at))
+
+	format = timeFormatter(bars(at, 24*time.Hour, 200), location, laplace.LocaleEn)
+	require.Equal(t, "3 Feb", format(at))
+
+	format = timeFormatter(bars(at, 24*time.Hour, 800), location, laplace.LocaleTr)
+	require.Equal(t, "Şub 2025", format(at))
+}
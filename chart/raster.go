@@ -0,0 +1,174 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"slices"
+)
+
+// raster is a canvas that draws into an image, without anti-aliasing
+type raster struct {
+	img *image.NRGBA
+}
+
+func newRaster(width, height int) *raster {
+	return &raster{img: image.NewNRGBA(image.Rect(0, 0, width, height))}
+}
+
+// blend paints the pixel at x, y with c over what is there
+func (r *raster) blend(x, y int, c color.NRGBA) {
+	if !(image.Point{x, y}.In(r.img.Rect)) {
+		return
+	}
+	if c.A == 0xFF {
+		r.img.SetNRGBA(x, y, c)
+		return
+	}
+
+	under := r.img.NRGBAAt(x, y)
+	alpha := float64(c.A) / 0xFF
+	mix := func(over, under uint8) uint8 {
+		return uint8(math.Round(float64(over)*alpha + float64(under)*(1-alpha)))
+	}
+	r.img.SetNRGBA(x, y, color.NRGBA{
+		R: mix(c.R, under.R),
+		G: mix(c.G, under.G),
+		B: mix(c.B, under.B),
+		A: uint8(math.Round(float64(c.A) + float64(under.A)*(1-alpha))),
+	})
+}
+
+func (r *raster) fillRect(x, y, width, height float64, c color.NRGBA) {
+	x0, y0 := int(math.Round(x)), int(math.Round(y))
+	x1, y1 := max(int(math.Round(x+width)), x0+1), max(int(math.Round(y+height)), y0+1)
+	for py := y0; py < y1; py++ {
+		for px := x0; px < x1; px++ {
+			r.blend(px, py, c)
+		}
+	}
+}
+
+// line draws a line with Bresenham's algorithm, thickened into a square brush for widths above
+// a pixel. Overlapping pixels of translucent lines are painted once per brush stroke.
+func (r *raster) line(x1, y1, x2, y2 float64, c color.NRGBA, width float64) {
+	r.stroke([]point{{x1, y1}, {x2, y2}}, c, width)
+}
+
+func (r *raster) polyline(points []point, c color.NRGBA, width float64) {
+	r.stroke(points, c, width)
+}
+
+func (r *raster) stroke(points []point, c color.NRGBA, width float64) {
+	brush := max(1, int(math.Round(width)))
+	painted := make(map[image.Point]bool)
+	plot := func(x, y int) {
+		for dy := 0; dy < brush; dy++ {
+			for dx := 0; dx < brush; dx++ {
+				p := image.Point{x + dx - brush/2, y + dy - brush/2}
+				if !painted[p] {
+					painted[p] = true
+					r.blend(p.X, p.Y, c)
+				}
+			}
+		}
+	}
+
+	for i := 1; i < len(points); i++ {
+		x0, y0 := int(math.Round(points[i-1].x)), int(math.Round(points[i-1].y))
+		x1, y1 := int(math.Round(points[i].x)), int(math.Round(points[i].y))
+		dx, dy := abs(x1-x0), -abs(y1-y0)
+		sx, sy := sign(x1-x0), sign(y1-y0)
+		err := dx + dy
+		for {
+			plot(x0, y0)
+			if x0 == x1 && y0 == y1 {
+				break
+			}
+			e2 := 2 * err
+			if e2 >= dy {
+				err += dy
+				x0 += sx
+			}
+			if e2 <= dx {
+				err += dx
+				y0 += sy
+			}
+		}
+	}
+}
+
+// polygon fills a polygon by scanlines with the even-odd rule
+func (r *raster) polygon(points []point, c color.NRGBA) {
+	if len(points) < 3 {
+		return
+	}
+	top, bottom := math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		top, bottom = math.Min(top, p.y), math.Max(bottom, p.y)
+	}
+
+	var crossings []float64
+	for y := int(math.Floor(top)); y <= int(math.Ceil(bottom)); y++ {
+		center := float64(y) + 0.5
+		crossings = crossings[:0]
+		for i := range points {
+			a, b := points[i], points[(i+1)%len(points)]
+			if (a.y <= center) != (b.y <= center) {
+				crossings = append(crossings, a.x+(center-a.y)/(b.y-a.y)*(b.x-a.x))
+			}
+		}
+		slices.Sort(crossings)
+		for i := 0; i+1 < len(crossings); i += 2 {
+			for x := int(math.Round(crossings[i])); x < int(math.Round(crossings[i+1])); x++ {
+				r.blend(x, y, c)
+			}
+		}
+	}
+}
+
+// text draws s in the bitmap font, each font pixel scale pixels wide
+func (r *raster) text(x, y float64, s string, c color.NRGBA, scale int, anchor anchor) {
+	left := int(math.Round(x))
+	switch anchor {
+	case anchorMiddle:
+		left -= textWidth(s, scale) / 2
+	case anchorEnd:
+		left -= textWidth(s, scale)
+	}
+	// The cap height spans the first 7 rows
+	top := int(math.Round(y)) - 7*scale/2
+
+	for _, char := range s {
+		for column, bits := range glyph(char) {
+			for row := 0; row < glyphHeight; row++ {
+				if bits&(1<<row) == 0 {
+					continue
+				}
+				for dy := 0; dy < scale; dy++ {
+					for dx := 0; dx < scale; dx++ {
+						r.blend(left+column*scale+dx, top+row*scale+dy, c)
+					}
+				}
+			}
+		}
+		left += glyphAdvance * scale
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,52 @@
+package chart
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRasterBlend(t *testing.T) {
+	r := newRaster(4, 4)
+	r.fillRect(0, 0, 4, 4, color.NRGBA{0xFF, 0xFF, 0xFF, 0xFF})
+	r.fillRect(1, 1, 2, 2, color.NRGBA{0x00, 0x00, 0xFF, 0x80})
+
+	require.Equal(t, color.NRGBA{0xFF, 0xFF, 0xFF, 0xFF}, r.img.NRGBAAt(0, 0))
+	require.Equal(t, color.NRGBA{0x7F, 0x7F, 0xFF, 0xFF}, r.img.NRGBAAt(1, 1))
+	require.Equal(t, color.NRGBA{0xFF, 0xFF, 0xFF, 0xFF}, r.img.NRGBAAt(3, 3))
+
+	// Drawing off the image is ignored
+	r.fillRect(-10, -10, 5, 5, color.NRGBA{A: 0xFF})
+}
+
+func TestRasterPolygon(t *testing.T) {
+	r := newRaster(10, 10)
+	fill := color.NRGBA{0xFF, 0x00, 0x00, 0xFF}
+	r.polygon([]point{{0, 0}, {10, 0}, {0, 10}}, fill)
+
+	require.Equal(t, fill, r.img.NRGBAAt(1, 1))
+	require.Equal(t, fill, r.img.NRGBAAt(7, 1))
+	require.Zero(t, r.img.NRGBAAt(8, 8).A)
+}
+
+func TestRasterLineAndText(t *testing.T) {
+	r := newRaster(20, 10)
+	stroke := color.NRGBA{0x00, 0xFF, 0x00, 0xFF}
+	r.line(0, 0, 19, 9, stroke, 1)
+	require.Equal(t, stroke, r.img.NRGBAAt(0, 0))
+	require.Equal(t, stroke, r.img.NRGBAAt(19, 9))
+
+	r = newRaster(20, 10)
+	r.text(0, 5, "I", stroke, 1, anchorStart)
+	// The stem of I is the middle column, from the top to the bottom of the cap height
+	top := 5 - 7/2
+	for row := 0; row < 7; row++ {
+		require.Equal(t, stroke, r.img.NRGBAAt(2, top+row), "row %d", row)
+	}
+	require.Zero(t, r.img.NRGBAAt(0, top+3).A)
+
+	require.Equal(t, 11, textWidth("ab", 1))
+	require.Equal(t, 22, textWidth("ab", 2))
+	require.Equal(t, glyphs['?'], glyph('€'))
+}
@@ -0,0 +1,80 @@
+package chart
+
+import (
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// svg is a canvas that writes SVG elements
+type svg struct {
+	width, height int
+	body          strings.Builder
+}
+
+func newSVG(width, height int) *svg {
+	return &svg{width: width, height: height}
+}
+
+func (s *svg) writeTo(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n%s</svg>\n",
+		s.width, s.height, s.width, s.height, s.body.String())
+	return err
+}
+
+func (s *svg) fillRect(x, y, width, height float64, c color.NRGBA) {
+	fmt.Fprintf(&s.body, "<rect x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\"%s/>\n",
+		svgNumber(x), svgNumber(y), svgNumber(width), svgNumber(height), svgPaint("fill", c))
+}
+
+func (s *svg) line(x1, y1, x2, y2 float64, c color.NRGBA, width float64) {
+	fmt.Fprintf(&s.body, "<line x1=\"%s\" y1=\"%s\" x2=\"%s\" y2=\"%s\"%s stroke-width=\"%s\"/>\n",
+		svgNumber(x1), svgNumber(y1), svgNumber(x2), svgNumber(y2), svgPaint("stroke", c), svgNumber(width))
+}
+
+func (s *svg) polyline(points []point, c color.NRGBA, width float64) {
+	fmt.Fprintf(&s.body, "<polyline points=\"%s\" fill=\"none\"%s stroke-width=\"%s\" stroke-linejoin=\"round\"/>\n",
+		svgPoints(points), svgPaint("stroke", c), svgNumber(width))
+}
+
+func (s *svg) polygon(points []point, c color.NRGBA) {
+	fmt.Fprintf(&s.body, "<polygon points=\"%s\"%s/>\n", svgPoints(points), svgPaint("fill", c))
+}
+
+func (s *svg) text(x, y float64, text string, c color.NRGBA, scale int, anchor anchor) {
+	anchors := [...]string{anchorStart: "start", anchorMiddle: "middle", anchorEnd: "end"}
+	// Monospaced text at this size advances about as far as the bitmap font of raster images
+	fmt.Fprintf(&s.body, "<text x=\"%s\" y=\"%s\" font-family=\"monospace\" font-size=\"%d\" text-anchor=\"%s\" dominant-baseline=\"central\"%s>%s</text>\n",
+		svgNumber(x), svgNumber(y), 10*scale, anchors[anchor], svgPaint("fill", c), html.EscapeString(text))
+}
+
+// svgNumber formats a coordinate with at most two decimals
+func svgNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+func svgPoints(points []point) string {
+	var b strings.Builder
+	for i, p := range points {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(svgNumber(p.x))
+		b.WriteByte(',')
+		b.WriteString(svgNumber(p.y))
+	}
+	return b.String()
+}
+
+// svgPaint returns the attributes that paint with c, with its alpha as opacity
+func svgPaint(attribute string, c color.NRGBA) string {
+	paint := fmt.Sprintf(" %s=\"#%02x%02x%02x\"", attribute, c.R, c.G, c.B)
+	if c.A != 0xFF {
+		paint += fmt.Sprintf(" %s-opacity=\"%s\"", attribute, strconv.FormatFloat(float64(c.A)/0xFF, 'f', 3, 64))
+	}
+	return paint
+}
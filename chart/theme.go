@@ -0,0 +1,56 @@
+package chart
+
+import "image/color"
+
+// Theme holds the colors of a chart. Colors with an alpha below 255 are blended with what is
+// drawn beneath them.
+type Theme struct {
+	Background color.NRGBA
+	Grid       color.NRGBA
+	Axis       color.NRGBA
+	Text       color.NRGBA
+	// Up and Down color the bars that closed above and below their open
+	Up   color.NRGBA
+	Down color.NRGBA
+	// Line colors line and area charts, and Area fills the latter
+	Line color.NRGBA
+	Area color.NRGBA
+	// Overlays are the colors overlays without one of their own are drawn in, in turn
+	Overlays []color.NRGBA
+}
+
+// LightTheme draws on a white background.
+var LightTheme = Theme{
+	Background: color.NRGBA{0xFF, 0xFF, 0xFF, 0xFF},
+	Grid:       color.NRGBA{0xE6, 0xE8, 0xEC, 0xFF},
+	Axis:       color.NRGBA{0xB0, 0xB4, 0xBC, 0xFF},
+	Text:       color.NRGBA{0x33, 0x37, 0x3F, 0xFF},
+	Up:         color.NRGBA{0x16, 0xA3, 0x4A, 0xFF},
+	Down:       color.NRGBA{0xDC, 0x26, 0x26, 0xFF},
+	Line:       color.NRGBA{0x25, 0x63, 0xEB, 0xFF},
+	Area:       color.NRGBA{0x25, 0x63, 0xEB, 0x40},
+	Overlays: []color.NRGBA{
+		{0xF5, 0x9E, 0x0B, 0xFF},
+		{0x7C, 0x3A, 0xED, 0xFF},
+		{0x08, 0x91, 0xB2, 0xFF},
+		{0xDB, 0x27, 0x77, 0xFF},
+	},
+}
+
+// DarkTheme draws on a near-black background.
+var DarkTheme = Theme{
+	Background: color.NRGBA{0x13, 0x17, 0x22, 0xFF},
+	Grid:       color.NRGBA{0x23, 0x29, 0x37, 0xFF},
+	Axis:       color.NRGBA{0x4B, 0x55, 0x63, 0xFF},
+	Text:       color.NRGBA{0xD1, 0xD5, 0xDB, 0xFF},
+	Up:         color.NRGBA{0x22, 0xC5, 0x5E, 0xFF},
+	Down:       color.NRGBA{0xEF, 0x44, 0x44, 0xFF},
+	Line:       color.NRGBA{0x60, 0xA5, 0xFA, 0xFF},
+	Area:       color.NRGBA{0x60, 0xA5, 0xFA, 0x40},
+	Overlays: []color.NRGBA{
+		{0xFB, 0xBF, 0x24, 0xFF},
+		{0xA7, 0x8B, 0xFA, 0xFF},
+		{0x22, 0xD3, 0xEE, 0xFF},
+		{0xF4, 0x72, 0xB6, 0xFF},
+	},
+}
@@ -1,13 +1,11 @@
 package laplace
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
 
 	"github.com/sirupsen/logrus"
 )
@@ -17,6 +15,8 @@ type Client struct {
 	baseUrl string
 	apiKey  string
 	logger  *logrus.Logger
+	codec   StreamCodec
+	metrics StreamMetrics
 }
 
 type clientOption func(*Client)
@@ -47,6 +47,7 @@ func NewClient(
 		baseUrl: cfg.BaseURL,
 		apiKey:  cfg.APIKey,
 		logger:  defaultLogger,
+		codec:   jsonStreamCodec{},
 	}
 
 	for _, opt := range opts {
@@ -138,38 +139,50 @@ func sendRawRequest(
 type LivePriceResult[T any] struct {
 	Data  T
 	Error error
+	// Delayed reports whether Data comes from the delayed feed rather than the live one.
+	Delayed bool
+
+	pooled *T
+	pool   *eventPool[T]
+}
+
+// Release hands a pooled event back to its stream for reuse. It must be called exactly once
+// when the consumer is done with Data on streams with pooled events enabled, and Data must not
+// be used afterwards. On other streams it is a no-op.
+func (r LivePriceResult[T]) Release() {
+	if r.pool != nil {
+		r.pool.put(r.pooled)
+	}
+}
+
+// sseStatusError converts a failed SSE handshake into an error. JSON error bodies are mapped to
+// LaplaceHTTPError the same way regular requests are, so callers can match them with errors.Is.
+func sseStatusError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	var msg LaplaceHTTPErrorMsg
+	if err := json.Unmarshal(body, &msg); err != nil || (msg.Message == "" && msg.ErrorCode == "") {
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	return getLaplaceError(&LaplaceHTTPError{
+		HTTPStatus: resp.StatusCode,
+		Message:    msg,
+	})
 }
 
 func sendSSERequest[T any](
 	ctx context.Context,
 	c *Client,
 	url string,
+	pool *eventPool[T],
 ) (<-chan LivePriceResult[T], func(), error) {
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// Set headers
-	req.Header.Set("Accept", "text/event-stream")
-	req.Header.Set("Cache-Control", "no-cache")
-	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	// Send the request
-	resp, err := c.cli.Do(req.WithContext(ctx))
+	resp, err := openSSE(ctx, c, url)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Check the response status
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		defer resp.Body.Close()
-		return nil, nil, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(bodyBytes))
-	}
-
 	// Create a single channel for results
 	results := make(chan LivePriceResult[T])
 
@@ -181,29 +194,60 @@ func sendSSERequest[T any](
 		defer close(results)
 		defer cancel()
 
-		scanner := bufio.NewScanner(resp.Body)
-		for scanner.Scan() {
+		send := func(result LivePriceResult[T]) bool {
 			select {
+			case results <- result:
+				return true
 			case <-ctxWithCancel.Done():
-				return
-			default:
-				line := scanner.Text()
-				if strings.HasPrefix(line, "data:") {
-					data := strings.TrimPrefix(line, "data:")
-					var event T
-					if err := json.Unmarshal([]byte(data), &event); err != nil {
-						results <- LivePriceResult[T]{Error: fmt.Errorf("error unmarshalling event: %w", err)}
-						continue
-					}
-					results <- LivePriceResult[T]{Data: event}
-				}
+				return false
 			}
 		}
 
-		if err := scanner.Err(); err != nil {
-			results <- LivePriceResult[T]{Error: fmt.Errorf("error reading SSE stream: %w", err)}
+		err := decodeSSE(resp.Body, c.codec, pool, send)
+
+		// A cancelled connection was closed on purpose, so its end is not reported
+		if ctxWithCancel.Err() == nil {
+			send(LivePriceResult[T]{Error: streamEndError(err)})
 		}
 	}()
 
 	return results, cancel, nil
 }
+
+// streamEndError is the terminal error of a connection that ended with the read error err,
+// which is nil when the server closed the stream
+func streamEndError(err error) error {
+	if err == nil {
+		err = ErrStreamEnded
+	}
+	return &StreamTransportError{Err: err}
+}
+
+// openSSE sends the SSE handshake request and returns the response whose body carries the
+// event stream.
+func openSSE(ctx context.Context, c *Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set headers
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("Connection", "keep-alive")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	// Send the request
+	resp, err := c.cli.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the response status
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, sseStatusError(resp)
+	}
+
+	return resp, nil
+}
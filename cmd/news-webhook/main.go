@@ -0,0 +1,89 @@
+// Command news-webhook forwards the Laplace live news stream to HTTP webhooks.
+//
+// The API key and base URL are read from the API_KEY and BASE_URL environment variables or a
+// .env file, and the signing secret from NEWS_WEBHOOK_SECRET unless -secret is given:
+//
+//	news-webhook -url https://example.com/hooks/news -region us -locale en -symbols AAPL,MSFT
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	laplace "github.com/Laplace-Analytics/laplace-api-golang"
+)
+
+func main() {
+	var (
+		urls        = flag.String("url", "", "comma-separated webhook URLs")
+		secret      = flag.String("secret", os.Getenv("NEWS_WEBHOOK_SECRET"), "HMAC-SHA256 signing secret")
+		envFile     = flag.String("env", "", "optional .env file to load configuration from")
+		region      = flag.String("region", string(laplace.RegionUs), "news region (us or tr)")
+		locale      = flag.String("locale", string(laplace.LocaleEn), "news locale (en or tr)")
+		lane        = flag.String("lane", "", "news lane")
+		symbols     = flag.String("symbols", "", "comma-separated symbols")
+		categories  = flag.String("categories", "", "comma-separated category IDs")
+		sources     = flag.String("sources", "", "comma-separated API sources")
+		deadLetter  = flag.String("dead-letter", "news-webhook-dead-letter.jsonl", "JSON Lines file for failed deliveries")
+		maxAttempts = flag.Int("max-attempts", 5, "delivery attempts per webhook before dead-lettering")
+		maxBackoff  = flag.Duration("max-backoff", time.Minute, "longest wait between delivery attempts")
+	)
+	flag.Parse()
+
+	if *urls == "" {
+		log.Fatal("at least one -url is required")
+	}
+
+	cfg, err := laplace.LoadGlobal(*envFile)
+	if err != nil {
+		log.Fatalf("failed to load configuration: %v", err)
+	}
+
+	logger := logrus.New()
+	client, err := laplace.NewClient(*cfg, laplace.WithLogger(logger))
+	if err != nil {
+		log.Fatalf("failed to create client: %v", err)
+	}
+
+	forwarder := client.NewNewsWebhookForwarder(laplace.StreamNewsParams{
+		Region:      laplace.Region(*region),
+		Locale:      laplace.Locale(*locale),
+		Lane:        laplace.NewsLane(*lane),
+		Symbols:     splitList(*symbols),
+		CategoryIds: splitList(*categories),
+		ApiSource:   splitList(*sources),
+	}, laplace.NewsWebhookOptions{
+		URLs:           splitList(*urls),
+		Secret:         *secret,
+		MaxAttempts:    *maxAttempts,
+		MaxBackoff:     *maxBackoff,
+		DeadLetterPath: *deadLetter,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Infof("forwarding %s news to %s", *region, *urls)
+	if err := forwarder.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		logger.Fatalf("news forwarding stopped: %v", err)
+	}
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
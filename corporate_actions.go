@@ -0,0 +1,401 @@
+package laplace
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// corporateActionsPageSize and corporateActionsMaxPages bound the capital increase paging of
+// GetCorporateActions
+const (
+	corporateActionsPageSize = 50
+	corporateActionsMaxPages = 20
+)
+
+// CorporateActionKind is the kind of a corporate action.
+type CorporateActionKind string
+
+const (
+	CorporateActionDividend CorporateActionKind = "dividend"
+	CorporateActionBonus    CorporateActionKind = "bonus"
+	CorporateActionRights   CorporateActionKind = "rights"
+)
+
+// CorporateAction is a dividend or capital increase that changes the price of a share on its
+// ex-date.
+type CorporateAction struct {
+	Kind CorporateActionKind
+	// ExDate is the first trading day without the entitlement. Only its calendar date on the
+	// exchange counts.
+	ExDate time.Time
+	// Rate is the number of new shares per existing share of a bonus or rights issue, so a
+	// 100% bonus issue has a rate of 1
+	Rate float64
+	// Price is the subscription price per new share of a rights issue
+	Price float64
+	// Amount is the dividend per share
+	Amount float64
+}
+
+// AdjustmentMode selects the corporate actions prices are adjusted for.
+type AdjustmentMode string
+
+const (
+	// AdjustmentModeSplitOnly adjusts for bonus and rights issues only, so that prices stay
+	// comparable across changes in the number of shares
+	AdjustmentModeSplitOnly AdjustmentMode = "split"
+	// AdjustmentModeDividend also adjusts for dividends, so that returns include them
+	AdjustmentModeDividend AdjustmentMode = "dividend"
+)
+
+// AdjustmentFactor is the factor the prices before an ex-date are multiplied by.
+type AdjustmentFactor struct {
+	ExDate time.Time
+	Factor float64
+	// ShareFactor is the part of Factor due to the change in the number of shares from bonus
+	// and rights issues, leaving dividends out. Volumes before the ex-date are divided by it.
+	ShareFactor float64
+	// Actions are the corporate actions of the ex-date the factor combines
+	Actions []CorporateAction
+}
+
+// CorporateActionsFromDividends converts dividends into corporate actions, using the gross
+// amount per share, or the net amount if net is set.
+func CorporateActionsFromDividends(dividends []StockDividend, net bool) []CorporateAction {
+	var actions []CorporateAction
+	for _, dividend := range dividends {
+		amount := dividend.GrossAmount
+		if net {
+			amount = dividend.NetAmount
+		}
+		if amount > 0 {
+			actions = append(actions, CorporateAction{Kind: CorporateActionDividend, ExDate: dividend.Date, Amount: amount})
+		}
+	}
+	return actions
+}
+
+// CorporateActionsFromCapitalIncreases converts the bonus and rights parts of capital
+// increases into corporate actions. Bonus shares from both capital reserves and dividends
+// count as bonus issues; the ex-dates are the bonus and rights start dates. Capital increases
+// that are not offered to the existing shareholders, and parts without a start date, such as
+// ones not yet approved, are skipped.
+func CorporateActionsFromCapitalIncreases(increases []CapitalIncrease) ([]CorporateAction, error) {
+	var actions []CorporateAction
+	for _, increase := range increases {
+		bonus, err := parsePercentRate(increase.BonusRate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bonus rate of capital increase %d: %w", increase.ID, err)
+		}
+		bonusDividend, err := parsePercentRate(increase.BonusDividendRate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bonus dividend rate of capital increase %d: %w", increase.ID, err)
+		}
+		rights, err := parsePercentRate(increase.RightsRate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rights rate of capital increase %d: %w", increase.ID, err)
+		}
+
+		if bonus+bonusDividend > 0 && increase.BonusStartDate != nil {
+			actions = append(actions, CorporateAction{Kind: CorporateActionBonus, ExDate: *increase.BonusStartDate, Rate: bonus + bonusDividend})
+		}
+		if rights > 0 && increase.RightsStartDate != nil {
+			price, err := parseDecimal(increase.RightsPrice)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rights price of capital increase %d: %w", increase.ID, err)
+			}
+			actions = append(actions, CorporateAction{Kind: CorporateActionRights, ExDate: *increase.RightsStartDate, Rate: rights, Price: price})
+		}
+	}
+	return actions, nil
+}
+
+// GetCorporateActions fetches the dividends and capital increases of a stock and returns them
+// as corporate actions, ordered by ex-date.
+func (c *Client) GetCorporateActions(ctx context.Context, symbol string, region Region) ([]CorporateAction, error) {
+	dividends, err := c.GetStockDividends(ctx, symbol, region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dividends: %w", err)
+	}
+	actions := CorporateActionsFromDividends(dividends, false)
+
+	var increases []CapitalIncrease
+	for page := 1; page <= corporateActionsMaxPages; page++ {
+		resp, err := c.GetCapitalIncreasesForInstrument(ctx, symbol, page, corporateActionsPageSize, region)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get capital increases: %w", err)
+		}
+		increases = append(increases, resp.Items...)
+		if len(resp.Items) < corporateActionsPageSize || len(increases) >= resp.RecordCount {
+			break
+		}
+	}
+
+	fromIncreases, err := CorporateActionsFromCapitalIncreases(increases)
+	if err != nil {
+		return nil, err
+	}
+	actions = append(actions, fromIncreases...)
+
+	slices.SortStableFunc(actions, func(a, b CorporateAction) int {
+		return strings.Compare(calendarDate(a.ExDate, region), calendarDate(b.ExDate, region))
+	})
+	return actions, nil
+}
+
+// AdjustmentFactors computes the adjustment factor of every ex-date from the unadjusted close
+// before it in points, which must be ordered by date. Actions sharing an ex-date are
+// combined: with bonus rate b, rights rate r at price p, dividend d and close P before the
+// ex-date, the theoretical ex-price is (P - d + r*p) / (1 + b + r), and the factor is its
+// ratio to P. The share factor is 1 / (1 + b + r). Ex-dates need a bar before them and one on
+// or after them, otherwise the close before them is unknown and they are skipped.
+func AdjustmentFactors(points []PriceDataPoint, actions []CorporateAction, mode AdjustmentMode, region Region) ([]AdjustmentFactor, error) {
+	if mode != AdjustmentModeSplitOnly && mode != AdjustmentModeDividend {
+		return nil, fmt.Errorf("invalid adjustment mode %q", mode)
+	}
+
+	byDate := make(map[string][]CorporateAction)
+	var dates []string
+	for _, action := range actions {
+		if action.Kind == CorporateActionDividend && mode == AdjustmentModeSplitOnly {
+			continue
+		}
+		date := calendarDate(action.ExDate, region)
+		if _, ok := byDate[date]; !ok {
+			dates = append(dates, date)
+		}
+		byDate[date] = append(byDate[date], action)
+	}
+	slices.Sort(dates)
+
+	var factors []AdjustmentFactor
+	for _, date := range dates {
+		// The close before the ex-date is the last one of an earlier day
+		cum := -1
+		for i, point := range points {
+			if point.ExchangeTime(region).Format(time.DateOnly) >= date {
+				break
+			}
+			cum = i
+		}
+		if cum < 0 || cum == len(points)-1 {
+			continue
+		}
+
+		cumClose := points[cum].UnadjustedClose
+		if cumClose <= 0 {
+			return nil, fmt.Errorf("no unadjusted close before %s, fetch the prices with detail", date)
+		}
+
+		var bonus, rights, subscription, dividend float64
+		for _, action := range byDate[date] {
+			switch action.Kind {
+			case CorporateActionBonus:
+				bonus += action.Rate
+			case CorporateActionRights:
+				rights += action.Rate
+				subscription += action.Rate * action.Price
+			case CorporateActionDividend:
+				dividend += action.Amount
+			}
+		}
+
+		factor := (cumClose - dividend + subscription) / ((1 + bonus + rights) * cumClose)
+		if factor <= 0 || math.IsNaN(factor) {
+			return nil, fmt.Errorf("invalid adjustment factor %g on %s", factor, date)
+		}
+		exDate, _ := time.ParseInLocation(time.DateOnly, date, region.Location())
+		factors = append(factors, AdjustmentFactor{
+			ExDate:      exDate,
+			Factor:      factor,
+			ShareFactor: 1 / (1 + bonus + rights),
+			Actions:     byDate[date],
+		})
+	}
+
+	return factors, nil
+}
+
+// AdjustPrices adjusts bars from their unadjusted prices: the prices of every bar before an
+// ex-date are multiplied by the product of the factors of the ex-dates after it, and its
+// volume divided by the product of their share factors, as dividends leave the number of
+// shares unchanged. The unadjusted fields are kept, and the bars must carry them.
+func AdjustPrices(points []PriceDataPoint, factors []AdjustmentFactor, region Region) ([]PriceDataPoint, error) {
+	adjusted := make([]PriceDataPoint, len(points))
+	for i, point := range points {
+		if point.UnadjustedClose <= 0 {
+			return nil, fmt.Errorf("bar at %s has no unadjusted prices, fetch the prices with detail", point.Time().Format(time.RFC3339))
+		}
+
+		factor, shareFactor := 1.0, 1.0
+		date := point.ExchangeTime(region).Format(time.DateOnly)
+		for _, f := range factors {
+			if date < calendarDate(f.ExDate, region) {
+				factor *= f.Factor
+				shareFactor *= f.ShareFactor
+			}
+		}
+
+		point.Open = point.UnadjustedOpen * factor
+		point.High = point.UnadjustedHigh * factor
+		point.Low = point.UnadjustedLow * factor
+		point.Close = point.UnadjustedClose * factor
+		point.Volume = point.UnadjustedVol / shareFactor
+		adjusted[i] = point
+	}
+	return adjusted, nil
+}
+
+// AdjustmentMismatch is a bar whose adjusted close differs between the server and the local
+// adjustment.
+type AdjustmentMismatch struct {
+	Time       time.Time
+	Unadjusted float64
+	Server     float64
+	Local      float64
+	// Difference is the relative difference of the local to the server close
+	Difference float64
+}
+
+// AdjustmentPeriod compares the adjustment of the bars between two ex-dates, where both the
+// server and the local adjustment apply a single factor.
+type AdjustmentPeriod struct {
+	From time.Time
+	To   time.Time
+	Bars int
+	// LocalFactor and ServerFactor are the median ratios of adjusted to unadjusted close
+	LocalFactor  float64
+	ServerFactor float64
+	Mismatches   int
+}
+
+// AdjustmentReconciliation compares adjusted prices from the server with a local adjustment.
+type AdjustmentReconciliation struct {
+	Compared      int
+	MaxDifference float64
+	Periods       []AdjustmentPeriod
+	Mismatches    []AdjustmentMismatch
+}
+
+// ReconcileAdjustments compares the adjusted closes the server returned with the ones of a
+// local adjustment of the same bars, such as one made with AdjustPrices. Bars differing by more
+// than tolerance, relatively, are reported, and the bars are grouped into periods between the
+// ex-dates of factors so that a disagreement can be traced to a corporate action.
+func ReconcileAdjustments(server, local []PriceDataPoint, factors []AdjustmentFactor, region Region, tolerance float64) (*AdjustmentReconciliation, error) {
+	localByDate := make(map[int64]PriceDataPoint, len(local))
+	for _, point := range local {
+		localByDate[point.Date] = point
+	}
+
+	boundaries := make([]string, len(factors))
+	for i, factor := range factors {
+		boundaries[i] = calendarDate(factor.ExDate, region)
+	}
+	slices.Sort(boundaries)
+
+	report := &AdjustmentReconciliation{}
+	var (
+		period                    *AdjustmentPeriod
+		periodIndex               = -1
+		localRatios, serverRatios []float64
+	)
+	closePeriod := func() {
+		if period == nil {
+			return
+		}
+		period.LocalFactor = median(localRatios)
+		period.ServerFactor = median(serverRatios)
+		report.Periods = append(report.Periods, *period)
+		period, localRatios, serverRatios = nil, nil, nil
+	}
+
+	sorted := slices.Clone(server)
+	slices.SortFunc(sorted, comparePriceDates)
+	for _, point := range sorted {
+		localPoint, ok := localByDate[point.Date]
+		if !ok {
+			continue
+		}
+		if point.UnadjustedClose <= 0 || point.Close <= 0 {
+			return nil, fmt.Errorf("bar at %s has no prices to compare", point.Time().Format(time.RFC3339))
+		}
+
+		at := point.ExchangeTime(region)
+		index, _ := slices.BinarySearch(boundaries, at.Format(time.DateOnly))
+		if exact := index < len(boundaries) && boundaries[index] == at.Format(time.DateOnly); exact {
+			index++
+		}
+		if period == nil || index != periodIndex {
+			closePeriod()
+			period = &AdjustmentPeriod{From: at}
+			periodIndex = index
+		}
+		period.To = at
+		period.Bars++
+		localRatios = append(localRatios, localPoint.Close/point.UnadjustedClose)
+		serverRatios = append(serverRatios, point.Close/point.UnadjustedClose)
+
+		report.Compared++
+		difference := localPoint.Close/point.Close - 1
+		report.MaxDifference = max(report.MaxDifference, math.Abs(difference))
+		if math.Abs(difference) > tolerance {
+			period.Mismatches++
+			report.Mismatches = append(report.Mismatches, AdjustmentMismatch{
+				Time:       at,
+				Unadjusted: point.UnadjustedClose,
+				Server:     point.Close,
+				Local:      localPoint.Close,
+				Difference: difference,
+			})
+		}
+	}
+	closePeriod()
+
+	return report, nil
+}
+
+// calendarDate returns the date of t on the exchange of region, where bars are dated too
+func calendarDate(t time.Time, region Region) string {
+	return t.In(region.Location()).Format(time.DateOnly)
+}
+
+// parsePercentRate parses a percentage such as "100", "%25" or "12,5" into a fraction; an
+// empty rate is 0
+func parsePercentRate(rate string) (float64, error) {
+	rate = strings.TrimSpace(strings.Trim(strings.TrimSpace(rate), "%"))
+	value, err := parseDecimal(rate)
+	return value / 100, err
+}
+
+// parseDecimal parses a decimal number written with a dot or a comma as decimal separator; an
+// empty string is 0
+func parseDecimal(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	if strings.Contains(value, ",") {
+		// Turkish notation, where dots group thousands
+		value = strings.ReplaceAll(value, ".", "")
+		value = strings.ReplaceAll(value, ",", ".")
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	if n := len(sorted); n%2 == 1 {
+		return sorted[n/2]
+	}
+	n := len(sorted)
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
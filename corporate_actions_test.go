@@ -0,0 +1,149 @@
+package laplace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePercentRate(t *testing.T) {
+	for rate, want := range map[string]float64{
+		"":         0,
+		"100":      1,
+		"%25":      0.25,
+		"12,5":     0.125,
+		" 1.234,5": 12.345,
+		"33.3333":  0.333333,
+	} {
+		got, err := parsePercentRate(rate)
+		require.NoError(t, err, rate)
+		require.InDelta(t, want, got, 1e-12, rate)
+	}
+
+	_, err := parsePercentRate("yüzde on")
+	require.Error(t, err)
+}
+
+func TestCorporateActionAdjustment(t *testing.T) {
+	day := func(i int) time.Time { return time.Date(2025, 1, 1+i, 0, 0, 0, 0, RegionTr.Location()) }
+	utcDay := func(i int) *time.Time {
+		d := time.Date(2025, 1, 1+i, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	// A 100% bonus issue halves the price on day 5, a dividend of 1 lowers it from 10 to 9 on day 7
+	unadjusted := []float64{20, 20, 20, 20, 20, 10, 10, 9, 9, 9}
+	points := make([]PriceDataPoint, len(unadjusted))
+	for i, price := range unadjusted {
+		points[i] = PriceDataPoint{
+			Date:            day(i).UnixMilli(),
+			UnadjustedOpen:  price,
+			UnadjustedHigh:  price,
+			UnadjustedLow:   price,
+			UnadjustedClose: price,
+			UnadjustedVol:   1000,
+		}
+	}
+
+	fromIncreases, err := CorporateActionsFromCapitalIncreases([]CapitalIncrease{
+		{ID: 1, BonusRate: "100", BonusStartDate: utcDay(5)},
+		{ID: 2, RightsRate: "50", RightsPrice: "1,00"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []CorporateAction{{Kind: CorporateActionBonus, ExDate: *utcDay(5), Rate: 1}}, fromIncreases)
+
+	actions := append(fromIncreases, CorporateActionsFromDividends([]StockDividend{{Date: *utcDay(7), GrossAmount: 1, NetAmount: 0.9}}, false)...)
+
+	splitFactors, err := AdjustmentFactors(points, actions, AdjustmentModeSplitOnly, RegionTr)
+	require.NoError(t, err)
+	require.Len(t, splitFactors, 1)
+	require.Equal(t, day(5), splitFactors[0].ExDate)
+	require.InDelta(t, 0.5, splitFactors[0].Factor, 1e-12)
+
+	// An ex-date is dated on the exchange, whatever the location of its time
+	shifted := []CorporateAction{{Kind: CorporateActionBonus, ExDate: day(5).UTC(), Rate: 1}}
+	shiftedFactors, err := AdjustmentFactors(points, shifted, AdjustmentModeSplitOnly, RegionTr)
+	require.NoError(t, err)
+	require.Len(t, shiftedFactors, 1)
+	require.Equal(t, day(5), shiftedFactors[0].ExDate)
+	require.InDelta(t, 0.5, shiftedFactors[0].Factor, 1e-12)
+
+	dividendFactors, err := AdjustmentFactors(points, actions, AdjustmentModeDividend, RegionTr)
+	require.NoError(t, err)
+	require.Len(t, dividendFactors, 2)
+	require.InDelta(t, 0.9, dividendFactors[1].Factor, 1e-12)
+	require.Equal(t, 1.0, dividendFactors[1].ShareFactor)
+
+	split, err := AdjustPrices(points, splitFactors, RegionTr)
+	require.NoError(t, err)
+	require.Equal(t, 10.0, split[0].Close)
+	require.Equal(t, 2000.0, split[0].Volume)
+	require.Equal(t, 20.0, split[0].UnadjustedClose)
+	require.Equal(t, 10.0, split[5].Close)
+
+	local, err := AdjustPrices(points, dividendFactors, RegionTr)
+	require.NoError(t, err)
+	closes := make([]float64, len(local))
+	for i, point := range local {
+		closes[i] = point.Close
+	}
+	require.InDeltaSlice(t, []float64{9, 9, 9, 9, 9, 9, 9, 9, 9, 9}, closes, 1e-12)
+
+	// The dividend leaves the number of shares, and so the volumes, unchanged
+	require.Equal(t, 2000.0, local[0].Volume)
+	require.Equal(t, 1000.0, local[5].Volume)
+
+	// The server adjusted for the bonus issue only, so the periods before the dividend disagree
+	report, err := ReconcileAdjustments(split, local, dividendFactors, RegionTr, 1e-6)
+	require.NoError(t, err)
+	require.Equal(t, 10, report.Compared)
+	require.InDelta(t, 0.1, report.MaxDifference, 1e-12)
+	require.Len(t, report.Mismatches, 7)
+	require.Len(t, report.Periods, 3)
+
+	require.Equal(t, day(0), report.Periods[0].From)
+	require.Equal(t, day(4), report.Periods[0].To)
+	require.InDelta(t, 0.45, report.Periods[0].LocalFactor, 1e-12)
+	require.InDelta(t, 0.5, report.Periods[0].ServerFactor, 1e-12)
+	require.Equal(t, 5, report.Periods[0].Mismatches)
+	require.Equal(t, 2, report.Periods[1].Mismatches)
+	require.Equal(t, 3, report.Periods[2].Bars)
+	require.Zero(t, report.Periods[2].Mismatches)
+
+	_, err = AdjustPrices([]PriceDataPoint{{Date: day(0).UnixMilli(), Close: 1}}, nil, RegionTr)
+	require.ErrorContains(t, err, "no unadjusted prices")
+}
+
+func TestGetCorporateActions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/stock/dividends":
+			json.NewEncoder(w).Encode([]StockDividend{{Date: time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC), GrossAmount: 2.5}})
+		case "/api/v1/capital-increase/THYAO":
+			require.Equal(t, "1", r.URL.Query().Get("page"))
+			start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+			json.NewEncoder(w).Encode(PaginatedResponse[CapitalIncrease]{
+				RecordCount: 1,
+				Items:       []CapitalIncrease{{ID: 7, RightsRate: "%20", RightsPrice: "1", RightsStartDate: &start}},
+			})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	client := newOfflineTestClient(t, srv.URL)
+	actions, err := client.GetCorporateActions(context.Background(), "THYAO", RegionTr)
+	require.NoError(t, err)
+	require.Len(t, actions, 2)
+	require.Equal(t, CorporateActionRights, actions[0].Kind)
+	require.InDelta(t, 0.2, actions[0].Rate, 1e-12)
+	require.Equal(t, 1.0, actions[0].Price)
+	require.Equal(t, CorporateActionDividend, actions[1].Kind)
+	require.Equal(t, 2.5, actions[1].Amount)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 )
 
 type LaplaceError error
@@ -15,9 +16,85 @@ var (
 	ErrEndpointIsNotActive          LaplaceError = errors.New("endpoint is not active")
 	ErrInvalidToken                 LaplaceError = errors.New("invalid token")
 	ErrInvalidID                    LaplaceError = errors.New("invalid object id")
+	ErrNoAccessToLevel              LaplaceError = errors.New("no access to data level")
 )
 
+// ErrStreamEnded is reported by a stream whose connection was closed by the server.
+var ErrStreamEnded LaplaceError = errors.New("stream closed by server")
+
+// StreamDecodeError reports a stream event that could not be decoded. It is recoverable: the
+// stream keeps delivering the events that follow.
+type StreamDecodeError struct {
+	Err error
+}
+
+func (e *StreamDecodeError) Error() string {
+	return "error unmarshalling event: " + e.Err.Error()
+}
+
+func (e *StreamDecodeError) Unwrap() error {
+	return e.Err
+}
+
+// StreamTransportError reports a stream connection that failed or was lost. It is terminal:
+// no further events arrive over the connection that reported it.
+type StreamTransportError struct {
+	Err error
+}
+
+func (e *StreamTransportError) Error() string {
+	return "error reading SSE stream: " + e.Err.Error()
+}
+
+func (e *StreamTransportError) Unwrap() error {
+	return e.Err
+}
+
+// LiveShardError reports the terminal error of one connection of a live price stream, which
+// streams a subset of its symbols. Symbols are no longer streamed, while the stream's other
+// connections carry on; an empty list means the connection streamed every symbol.
+type LiveShardError struct {
+	Symbols []string
+	Err     error
+}
+
+func (e *LiveShardError) Error() string {
+	if len(e.Symbols) == 0 {
+		return "live price connection for every symbol failed: " + e.Err.Error()
+	}
+	return fmt.Sprintf("live price connection for %s failed: %s", strings.Join(e.Symbols, ","), e.Err)
+}
+
+func (e *LiveShardError) Unwrap() error {
+	return e.Err
+}
+
+// NewsBackfillTruncatedError reports a news stream backfill that stopped at its page limit
+// after a reconnect, so news published between From and the live connection may be missing.
+// It is recoverable: the stream carries on with the live news.
+type NewsBackfillTruncatedError struct {
+	From  time.Time
+	Pages int
+}
+
+func (e *NewsBackfillTruncatedError) Error() string {
+	return fmt.Sprintf("news backfill truncated after %d pages at %s", e.Pages, e.From.UTC().Format(time.RFC3339))
+}
+
+// IsRecoverable reports whether a stream result error leaves the stream usable. Decode errors
+// and truncated backfills are recoverable; transport and authorization errors are terminal.
+func IsRecoverable(err error) bool {
+	var decodeErr *StreamDecodeError
+	var truncatedErr *NewsBackfillTruncatedError
+	return errors.As(err, &decodeErr) || errors.As(err, &truncatedErr)
+}
+
 func getLaplaceError(httpErr *LaplaceHTTPError) *LaplaceHTTPError {
+	if httpErr.Message.ErrorCode == string(MessageCodeHasNoAccessToLevel) {
+		httpErr.InternalError = ErrNoAccessToLevel
+		return httpErr
+	}
+
 	switch httpErr.HTTPStatus {
 	case http.StatusForbidden:
 		switch httpErr.Message.Message {
@@ -0,0 +1,491 @@
+package laplace
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultEventStudyBenchmarkTR is the benchmark of Turkish event studies unless another one is set.
+const DefaultEventStudyBenchmarkTR = "XU100"
+
+// EventWindow is a range of bars around an event, inclusive on both ends. Bar 0 is the bar the
+// news was published in, so {-1, 1} spans the bar before the news to the bar after it.
+type EventWindow struct {
+	From int
+	To   int
+}
+
+func (w EventWindow) String() string {
+	return fmt.Sprintf("[%+d,%+d]", w.From, w.To)
+}
+
+// EventStudyOptions configures an event study.
+type EventStudyOptions struct {
+	// Region of the studied stocks and the benchmark
+	Region Region
+	// Benchmark is the symbol abnormal returns are measured against, DefaultEventStudyBenchmarkTR
+	// by default for RegionTr. It is required for RegionUs.
+	Benchmark string
+	// Interval of the bars, HistoricalPriceIntervalOneDay by default
+	Interval HistoricalPriceInterval
+	// Windows are the ranges cumulative abnormal returns are computed over, [-1,+1] and [0,+5]
+	// by default
+	Windows []EventWindow
+	// EstimationBars is the number of bars before the earliest window used to fit a market
+	// model (alpha and beta against the benchmark). With 0, abnormal returns are simply the
+	// stock return minus the benchmark return.
+	EstimationBars int
+	// QualityBuckets are the ascending lower bounds of the QualityScore buckets results are
+	// grouped by, 0, 4 and 7 by default
+	QualityBuckets []int64
+	// Concurrency is the number of events fetched in parallel, 4 by default
+	Concurrency int
+}
+
+// EventStudyResult is the price reaction of one ticker to one news item.
+type EventStudyResult struct {
+	News   NewsV2
+	Symbol string
+	// EventBar is the start of bar 0 in the exchange's time zone
+	EventBar time.Time
+	// Alpha and Beta are the fitted market model, 0 and 1 without an estimation window
+	Alpha float64
+	Beta  float64
+	// AbnormalReturns holds the abnormal return of every bar from the start of the earliest
+	// window to the end of the latest one; AbnormalReturns[0] is bar FirstBar
+	AbnormalReturns []float64
+	FirstBar        int
+	// CAR is the cumulative abnormal return of every window
+	CAR map[EventWindow]float64
+	// Err is set when the event could not be studied, such as when too few bars were
+	// available around it. Failed events are left out of the aggregates.
+	Err error
+}
+
+// EventStudyStats summarizes the cumulative abnormal returns of a group of events in a window.
+type EventStudyStats struct {
+	Count  int
+	Mean   float64
+	Median float64
+	StdDev float64
+	// TStat tests the mean against zero; it is 0 for fewer than two events
+	TStat float64
+	// Positive is the share of events with a positive cumulative abnormal return
+	Positive float64
+}
+
+// EventStudyGroup holds the statistics of the events sharing a category, publisher or quality
+// bucket.
+type EventStudyGroup struct {
+	Key   string
+	Count int
+	Stats map[EventWindow]EventStudyStats
+}
+
+// EventStudyReport is the outcome of an event study.
+type EventStudyReport struct {
+	Windows []EventWindow
+	Events  []EventStudyResult
+	// Overall aggregates every successfully studied event
+	Overall     EventStudyGroup
+	ByCategory  []EventStudyGroup
+	ByPublisher []EventStudyGroup
+	ByQuality   []EventStudyGroup
+}
+
+// RunEventStudy measures how the tickers of news items reacted to them. For every ticker of
+// every news item, bars around the publication time are fetched through
+// GetCustomHistoricalPrices along with the benchmark, abnormal returns are computed bar by bar
+// and cumulated over the windows, and the results are aggregated by category, publisher and
+// quality score. News without tickers is skipped; an event that cannot be studied is reported
+// through its Err field rather than failing the study.
+func (c *Client) RunEventStudy(ctx context.Context, news []NewsV2, opts EventStudyOptions) (*EventStudyReport, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	type event struct {
+		news   NewsV2
+		symbol string
+	}
+	var events []event
+	for _, item := range news {
+		for _, ticker := range item.Tickers {
+			if ticker.Symbol != "" {
+				events = append(events, event{item, strings.ToUpper(ticker.Symbol)})
+			}
+		}
+	}
+
+	fetcher := &eventStudyFetcher{c: c, opts: opts, cache: make(map[string]*eventStudyFetch)}
+	report := &EventStudyReport{Windows: opts.Windows, Events: make([]EventStudyResult, len(events))}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, opts.Concurrency)
+	for i, e := range events {
+		wg.Add(1)
+		go func(i int, e event) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				report.Events[i] = EventStudyResult{News: e.news, Symbol: e.symbol, Err: ctx.Err()}
+				return
+			}
+			report.Events[i] = fetcher.study(ctx, e.news, e.symbol)
+		}(i, e)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	report.aggregate(opts)
+	return report, nil
+}
+
+func (o EventStudyOptions) withDefaults() (EventStudyOptions, error) {
+	if o.Region != RegionTr && o.Region != RegionUs {
+		return o, fmt.Errorf("invalid event study region %q", o.Region)
+	}
+	if o.Benchmark == "" {
+		if o.Region != RegionTr {
+			return o, fmt.Errorf("event study benchmark is required for region %q", o.Region)
+		}
+		o.Benchmark = DefaultEventStudyBenchmarkTR
+	}
+	if o.Interval == "" {
+		o.Interval = HistoricalPriceIntervalOneDay
+	}
+	if o.Interval.Duration() == 0 {
+		return o, fmt.Errorf("unsupported event study interval %q", o.Interval)
+	}
+	if len(o.Windows) == 0 {
+		o.Windows = []EventWindow{{-1, 1}, {0, 5}}
+	}
+	for _, window := range o.Windows {
+		if window.From > window.To {
+			return o, fmt.Errorf("event window %s ends before it starts", window)
+		}
+	}
+	if o.EstimationBars < 0 {
+		return o, fmt.Errorf("estimation window cannot be negative")
+	}
+	if o.EstimationBars == 1 {
+		return o, fmt.Errorf("estimation window needs at least 2 bars")
+	}
+	if len(o.QualityBuckets) == 0 {
+		o.QualityBuckets = []int64{0, 4, 7}
+	}
+	if !slices.IsSorted(o.QualityBuckets) {
+		return o, fmt.Errorf("quality buckets must be ascending")
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 4
+	}
+	return o, nil
+}
+
+// barRange returns the first and last bar spanned by the windows, relative to the event bar
+func (o EventStudyOptions) barRange() (int, int) {
+	first, last := o.Windows[0].From, o.Windows[0].To
+	for _, window := range o.Windows[1:] {
+		first = min(first, window.From)
+		last = max(last, window.To)
+	}
+	return first, last
+}
+
+// eventStudyFetch is a price request shared by the events that need it
+type eventStudyFetch struct {
+	done chan struct{}
+	bars []PriceDataPoint
+	err  error
+}
+
+type eventStudyFetcher struct {
+	c    *Client
+	opts EventStudyOptions
+
+	mu    sync.Mutex
+	cache map[string]*eventStudyFetch
+}
+
+// bars fetches the bars of symbol between two dates, sharing the request with any other
+// event asking for the same range
+func (f *eventStudyFetcher) bars(ctx context.Context, symbol, from, to string) ([]PriceDataPoint, error) {
+	key := symbol + "|" + from + "|" + to
+
+	f.mu.Lock()
+	fetch, ok := f.cache[key]
+	if !ok {
+		fetch = &eventStudyFetch{done: make(chan struct{})}
+		f.cache[key] = fetch
+	}
+	f.mu.Unlock()
+
+	if !ok {
+		fetch.bars, fetch.err = f.c.GetCustomHistoricalPrices(ctx, symbol, f.opts.Region, from, to, f.opts.Interval, false)
+		close(fetch.done)
+	}
+
+	select {
+	case <-fetch.done:
+		return fetch.bars, fetch.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// study fetches the bars around one event and computes its abnormal returns
+func (f *eventStudyFetcher) study(ctx context.Context, news NewsV2, symbol string) EventStudyResult {
+	result := EventStudyResult{News: news, Symbol: symbol}
+
+	first, last := f.opts.barRange()
+	before := f.opts.EstimationBars + max(-first, 0) + 1
+	after := max(last, 0)
+
+	// Bars only exist during sessions, so the requested range is widened to cover nights,
+	// weekends and holidays; dates are requested whole on the exchange, as the bars are matched
+	// by time anyway
+	step := f.opts.Interval.Duration()
+	from := news.Timestamp.Add(-time.Duration(before)*step*3 - 5*24*time.Hour)
+	to := news.Timestamp.Add(time.Duration(after)*step*3 + 5*24*time.Hour)
+	location := f.opts.Region.Location()
+	fromDate, toDate := from.In(location).Format(time.DateOnly), to.In(location).Format(time.DateOnly)
+
+	stock, err := f.bars(ctx, symbol, fromDate, toDate)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to get %s prices: %w", symbol, err)
+		return result
+	}
+	benchmark, err := f.bars(ctx, f.opts.Benchmark, fromDate, toDate)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to get %s benchmark prices: %w", f.opts.Benchmark, err)
+		return result
+	}
+
+	return computeEventStudy(result, stock, benchmark, f.opts)
+}
+
+// computeEventStudy fills result with the abnormal returns of stock around the news of result
+func computeEventStudy(result EventStudyResult, stock, benchmark []PriceDataPoint, opts EventStudyOptions) EventStudyResult {
+	stock = slices.Clone(stock)
+	slices.SortFunc(stock, comparePriceDates)
+	benchmarkCloses := make(map[int64]float64, len(benchmark))
+	for _, bar := range benchmark {
+		benchmarkCloses[bar.Date] = bar.Close
+	}
+
+	// Bar 0 is the last bar starting at or before the news
+	event := sort.Search(len(stock), func(i int) bool { return stock[i].Time().After(result.News.Timestamp) }) - 1
+	if event < 0 {
+		result.Err = fmt.Errorf("no %s bars before the news", result.Symbol)
+		return result
+	}
+	result.EventBar = stock[event].ExchangeTime(opts.Region)
+
+	// returns computes the stock and benchmark returns of bars [from, to] relative to the event
+	returns := func(from, to int) ([]float64, []float64, error) {
+		if event+from-1 < 0 || event+to >= len(stock) {
+			return nil, nil, fmt.Errorf("not enough %s bars around the news, need bars %+d to %+d", result.Symbol, from-1, to)
+		}
+		var stockReturns, benchmarkReturns []float64
+		for i := event + from; i <= event+to; i++ {
+			previous, current := stock[i-1], stock[i]
+			previousBenchmark, ok1 := benchmarkCloses[previous.Date]
+			currentBenchmark, ok2 := benchmarkCloses[current.Date]
+			if !ok1 || !ok2 {
+				return nil, nil, fmt.Errorf("missing benchmark bar for %s", current.Time().Format(time.RFC3339))
+			}
+			if previous.Close == 0 || previousBenchmark == 0 {
+				return nil, nil, fmt.Errorf("zero close price at %s", previous.Time().Format(time.RFC3339))
+			}
+			stockReturns = append(stockReturns, current.Close/previous.Close-1)
+			benchmarkReturns = append(benchmarkReturns, currentBenchmark/previousBenchmark-1)
+		}
+		return stockReturns, benchmarkReturns, nil
+	}
+
+	first, last := opts.barRange()
+	result.Alpha, result.Beta = 0, 1
+	if opts.EstimationBars > 0 {
+		stockReturns, benchmarkReturns, err := returns(first-opts.EstimationBars, first-1)
+		if err != nil {
+			result.Err = fmt.Errorf("estimation window: %w", err)
+			return result
+		}
+		result.Alpha, result.Beta = fitMarketModel(stockReturns, benchmarkReturns)
+	}
+
+	stockReturns, benchmarkReturns, err := returns(first, last)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.FirstBar = first
+	result.AbnormalReturns = make([]float64, len(stockReturns))
+	for i := range stockReturns {
+		result.AbnormalReturns[i] = stockReturns[i] - result.Alpha - result.Beta*benchmarkReturns[i]
+	}
+
+	result.CAR = make(map[EventWindow]float64, len(opts.Windows))
+	for _, window := range opts.Windows {
+		var car float64
+		for bar := window.From; bar <= window.To; bar++ {
+			car += result.AbnormalReturns[bar-first]
+		}
+		result.CAR[window] = car
+	}
+
+	return result
+}
+
+// fitMarketModel fits stock = alpha + beta * benchmark by least squares
+func fitMarketModel(stock, benchmark []float64) (float64, float64) {
+	stockMean, _ := meanStdDev(stock)
+	benchmarkMean, _ := meanStdDev(benchmark)
+
+	var covariance, variance float64
+	for i := range stock {
+		covariance += (stock[i] - stockMean) * (benchmark[i] - benchmarkMean)
+		variance += (benchmark[i] - benchmarkMean) * (benchmark[i] - benchmarkMean)
+	}
+	if variance == 0 {
+		return stockMean - benchmarkMean, 1
+	}
+
+	beta := covariance / variance
+	return stockMean - beta*benchmarkMean, beta
+}
+
+// aggregate groups the successfully studied events
+func (r *EventStudyReport) aggregate(opts EventStudyOptions) {
+	var studied []EventStudyResult
+	for _, event := range r.Events {
+		if event.Err == nil {
+			studied = append(studied, event)
+		}
+	}
+
+	r.Overall = newEventStudyGroup("all", studied, r.Windows)
+	r.ByCategory = groupEventStudy(studied, r.Windows, func(event EventStudyResult) string {
+		if event.News.Categories == nil {
+			return ""
+		}
+		return event.News.Categories.Name
+	})
+	r.ByPublisher = groupEventStudy(studied, r.Windows, func(event EventStudyResult) string {
+		return event.News.Publisher.Name
+	})
+	r.ByQuality = groupEventStudy(studied, r.Windows, func(event EventStudyResult) string {
+		return qualityBucket(event.News.QualityScore, opts.QualityBuckets)
+	})
+
+	// Quality buckets read best in score order rather than by size
+	order := map[string]int{fmt.Sprintf("<%d", opts.QualityBuckets[0]): -1}
+	for i, bound := range opts.QualityBuckets {
+		order[qualityBucket(bound, opts.QualityBuckets)] = i
+	}
+	slices.SortFunc(r.ByQuality, func(a, b EventStudyGroup) int {
+		return order[a.Key] - order[b.Key]
+	})
+}
+
+// qualityBucket labels the bucket a quality score falls in, such as "4-6" or "7+"
+func qualityBucket(score int64, bounds []int64) string {
+	i := sort.Search(len(bounds), func(i int) bool { return bounds[i] > score }) - 1
+	switch {
+	case i < 0:
+		return fmt.Sprintf("<%d", bounds[0])
+	case i == len(bounds)-1:
+		return fmt.Sprintf("%d+", bounds[i])
+	default:
+		return fmt.Sprintf("%d-%d", bounds[i], bounds[i+1]-1)
+	}
+}
+
+// groupEventStudy groups events by key, largest group first; events with an empty key are
+// grouped under "unknown"
+func groupEventStudy(events []EventStudyResult, windows []EventWindow, key func(EventStudyResult) string) []EventStudyGroup {
+	grouped := make(map[string][]EventStudyResult)
+	for _, event := range events {
+		k := key(event)
+		if k == "" {
+			k = "unknown"
+		}
+		grouped[k] = append(grouped[k], event)
+	}
+
+	groups := make([]EventStudyGroup, 0, len(grouped))
+	for k, members := range grouped {
+		groups = append(groups, newEventStudyGroup(k, members, windows))
+	}
+	slices.SortFunc(groups, func(a, b EventStudyGroup) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	return groups
+}
+
+func newEventStudyGroup(key string, events []EventStudyResult, windows []EventWindow) EventStudyGroup {
+	group := EventStudyGroup{Key: key, Count: len(events), Stats: make(map[EventWindow]EventStudyStats, len(windows))}
+	for _, window := range windows {
+		cars := make([]float64, len(events))
+		for i, event := range events {
+			cars[i] = event.CAR[window]
+		}
+		group.Stats[window] = newEventStudyStats(cars)
+	}
+	return group
+}
+
+func newEventStudyStats(values []float64) EventStudyStats {
+	stats := EventStudyStats{Count: len(values)}
+	if len(values) == 0 {
+		return stats
+	}
+
+	positive := 0
+	for _, v := range values {
+		if v > 0 {
+			positive++
+		}
+	}
+	stats.Positive = float64(positive) / float64(len(values))
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	if n := len(sorted); n%2 == 1 {
+		stats.Median = sorted[n/2]
+	} else {
+		stats.Median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+
+	stats.Mean, _ = meanStdDev(values)
+	if len(values) > 1 {
+		var squares float64
+		for _, v := range values {
+			squares += (v - stats.Mean) * (v - stats.Mean)
+		}
+		// Sample standard deviation, as the events are a sample of all possible ones
+		stats.StdDev = math.Sqrt(squares / float64(len(values)-1))
+		if stats.StdDev > 0 {
+			stats.TStat = stats.Mean / (stats.StdDev / math.Sqrt(float64(len(values))))
+		}
+	}
+
+	return stats
+}
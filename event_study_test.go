@@ -0,0 +1,146 @@
+package laplace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunEventStudy(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := func(i int) time.Time { return start.AddDate(0, 0, i) }
+
+	// flat returns daily bars at a constant close, which moves by jump from bar jumpAt on
+	flat := func(close, jump float64, jumpAt int) []PriceDataPoint {
+		bars := make([]PriceDataPoint, 30)
+		for i := range bars {
+			bars[i] = PriceDataPoint{Date: day(i).UnixMilli(), Close: close}
+			if i >= jumpAt {
+				bars[i].Close = close * (1 + jump)
+			}
+		}
+		return bars
+	}
+	series := map[string][]PriceDataPoint{
+		"XU100": flat(10000, 0, 0),
+		"THYAO": flat(300, 0.1, 10),
+		"GARAN": flat(100, -0.05, 20),
+		"ASELS": nil,
+	}
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		q := r.URL.Query()
+		require.Equal(t, "/api/v1/stock/price/interval", r.URL.Path)
+		require.Equal(t, "1d", q.Get("interval"))
+		require.Regexp(t, `^\d{4}-\d{2}-\d{2}$`, q.Get("fromDate"))
+		json.NewEncoder(w).Encode(series[q.Get("stock")])
+	}))
+	defer srv.Close()
+
+	news := []NewsV2{
+		{
+			ID:           "n1",
+			Timestamp:    day(10).Add(14 * time.Hour),
+			Publisher:    NewsPublisher{Name: "KAP"},
+			QualityScore: 8,
+			Categories:   &NewsCategories{ID: "earnings", Name: "Earnings"},
+			Tickers:      []NewsTicker{{Symbol: "thyao"}, {Symbol: "ASELS"}},
+		},
+		{
+			ID:           "n2",
+			Timestamp:    day(20).Add(9 * time.Hour),
+			Publisher:    NewsPublisher{Name: "KAP"},
+			QualityScore: 5,
+			Tickers:      []NewsTicker{{Symbol: "GARAN"}},
+		},
+		{ID: "n3", Timestamp: day(15)},
+	}
+
+	client := newOfflineTestClient(t, srv.URL)
+	report, err := client.RunEventStudy(context.Background(), news, EventStudyOptions{Region: RegionTr})
+	require.NoError(t, err)
+
+	require.Len(t, report.Events, 3)
+	thyao, asels, garan := report.Events[0], report.Events[1], report.Events[2]
+
+	require.NoError(t, thyao.Err)
+	require.Equal(t, "THYAO", thyao.Symbol)
+	require.Equal(t, day(10), thyao.EventBar.UTC())
+	require.Equal(t, -1, thyao.FirstBar)
+	require.InDeltaSlice(t, []float64{0, 0.1, 0, 0, 0, 0, 0}, thyao.AbnormalReturns, 1e-9)
+	require.InDelta(t, 0.1, thyao.CAR[EventWindow{-1, 1}], 1e-9)
+	require.InDelta(t, 0.1, thyao.CAR[EventWindow{0, 5}], 1e-9)
+
+	require.ErrorContains(t, asels.Err, "no ASELS bars before the news")
+
+	require.NoError(t, garan.Err)
+	require.InDelta(t, -0.05, garan.CAR[EventWindow{-1, 1}], 1e-9)
+
+	// The benchmark range is shared by both events of the first news item
+	require.EqualValues(t, 5, requests.Load())
+
+	overall := report.Overall.Stats[EventWindow{-1, 1}]
+	require.Equal(t, 2, report.Overall.Count)
+	require.InDelta(t, 0.025, overall.Mean, 1e-9)
+	require.InDelta(t, 0.025, overall.Median, 1e-9)
+	require.Equal(t, 0.5, overall.Positive)
+
+	require.Len(t, report.ByPublisher, 1)
+	require.Equal(t, "KAP", report.ByPublisher[0].Key)
+
+	require.Equal(t, []string{"Earnings", "unknown"}, groupKeys(report.ByCategory))
+	require.Equal(t, []string{"4-6", "7+"}, groupKeys(report.ByQuality))
+
+	_, err = client.RunEventStudy(context.Background(), news, EventStudyOptions{Region: RegionUs})
+	require.ErrorContains(t, err, "benchmark is required")
+}
+
+func TestEventStudyRequestsExchangeDates(t *testing.T) {
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		ranges = append(ranges, q.Get("fromDate")+"/"+q.Get("toDate"))
+		json.NewEncoder(w).Encode([]PriceDataPoint{})
+	}))
+	defer srv.Close()
+
+	// 22:30 UTC is already the next day in Istanbul
+	news := []NewsV2{{ID: "n1", Timestamp: time.Date(2025, 1, 10, 22, 30, 0, 0, time.UTC), Tickers: []NewsTicker{{Symbol: "THYAO"}}}}
+
+	client := newOfflineTestClient(t, srv.URL)
+	_, err := client.RunEventStudy(context.Background(), news, EventStudyOptions{Region: RegionTr, Windows: []EventWindow{{0, 1}}, Concurrency: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{"2025-01-03/2025-01-19", "2025-01-03/2025-01-19"}, ranges)
+}
+
+func TestEventStudyMarketModel(t *testing.T) {
+	benchmark := []float64{0.01, -0.02, 0.015, 0.003, -0.007}
+	stock := make([]float64, len(benchmark))
+	for i, r := range benchmark {
+		stock[i] = 0.001 + 1.5*r
+	}
+
+	alpha, beta := fitMarketModel(stock, benchmark)
+	require.InDelta(t, 0.001, alpha, 1e-12)
+	require.InDelta(t, 1.5, beta, 1e-12)
+
+	require.Equal(t, "<0", qualityBucket(-1, []int64{0, 4, 7}))
+	require.Equal(t, "0-3", qualityBucket(3, []int64{0, 4, 7}))
+	require.Equal(t, "7+", qualityBucket(10, []int64{0, 4, 7}))
+}
+
+func groupKeys(groups []EventStudyGroup) []string {
+	keys := make([]string, len(groups))
+	for i, group := range groups {
+		keys[i] = group.Key
+	}
+	return keys
+}
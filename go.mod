@@ -1,6 +1,6 @@
 module github.com/Laplace-Analytics/laplace-api-golang
 
-go 1.22.1
+go 1.23
 
 require (
 	github.com/google/uuid v1.6.0
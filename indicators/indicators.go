@@ -0,0 +1,95 @@
+// Package indicators computes technical indicators over laplace.PriceDataPoint series.
+//
+// Every indicator comes in two forms. The streaming form is a type updated one value or bar at
+// a time, for instance from a live price stream, that reports whether it has seen enough input
+// to produce a value. The batch form runs it over a whole series and returns one value per bar,
+// NaN for the bars before the indicator is ready, so that results line up with their input.
+package indicators
+
+import (
+	"fmt"
+	"math"
+
+	laplace "github.com/Laplace-Analytics/laplace-api-golang"
+)
+
+// Closes returns the close prices of points.
+func Closes(points []laplace.PriceDataPoint) []float64 {
+	closes := make([]float64, len(points))
+	for i, point := range points {
+		closes[i] = point.Close
+	}
+	return closes
+}
+
+func checkPeriod(name string, period int) error {
+	if period <= 0 {
+		return fmt.Errorf("invalid %s period %d", name, period)
+	}
+	return nil
+}
+
+// batch runs update over inputs, storing missing for the inputs before it is ready
+func batch[T, V any](inputs []T, update func(T) (V, bool), missing V) []V {
+	values := make([]V, len(inputs))
+	for i, input := range inputs {
+		value, ok := update(input)
+		if !ok {
+			value = missing
+		}
+		values[i] = value
+	}
+	return values
+}
+
+// batchCloses runs update over the close prices of points
+func batchCloses(points []laplace.PriceDataPoint, update func(float64) (float64, bool)) []float64 {
+	return batch(Closes(points), update, math.NaN())
+}
+
+// window holds the last values of a series
+type window struct {
+	values []float64
+	next   int
+	count  int
+}
+
+func newWindow(size int) *window {
+	return &window{values: make([]float64, size)}
+}
+
+// push adds a value, returning the value it evicted and whether the window was full
+func (w *window) push(value float64) (float64, bool) {
+	evicted, full := w.values[w.next], w.full()
+	w.values[w.next] = value
+	w.next = (w.next + 1) % len(w.values)
+	if !full {
+		w.count++
+	}
+	return evicted, full
+}
+
+func (w *window) full() bool {
+	return w.count == len(w.values)
+}
+
+// at returns the i-th value held, oldest first
+func (w *window) at(i int) float64 {
+	return w.values[(w.next-w.count+i+len(w.values))%len(w.values)]
+}
+
+func (w *window) min() float64 {
+	low := math.Inf(1)
+	for i := 0; i < w.count; i++ {
+		low = math.Min(low, w.at(i))
+	}
+	return low
+}
+
+func (w *window) max() float64 {
+	high := math.Inf(-1)
+	for i := 0; i < w.count; i++ {
+		high = math.Max(high, w.at(i))
+	}
+	return high
+}
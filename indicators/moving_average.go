@@ -0,0 +1,133 @@
+package indicators
+
+import (
+	"math"
+
+	laplace "github.com/Laplace-Analytics/laplace-api-golang"
+)
+
+// SMA is a simple moving average: the mean of the last period values.
+type SMA struct {
+	window *window
+	sum    float64
+}
+
+// NewSMA returns a simple moving average over period values.
+func NewSMA(period int) (*SMA, error) {
+	if err := checkPeriod("SMA", period); err != nil {
+		return nil, err
+	}
+	return &SMA{window: newWindow(period)}, nil
+}
+
+// Update adds a value and returns the average, which is ready once period values were added.
+func (s *SMA) Update(value float64) (float64, bool) {
+	evicted, full := s.window.push(value)
+	s.sum += value
+	if full {
+		s.sum -= evicted
+	}
+	if !s.window.full() {
+		return math.NaN(), false
+	}
+	return s.sum / float64(len(s.window.values)), true
+}
+
+// SMASeries returns the simple moving average of the close prices of points.
+func SMASeries(points []laplace.PriceDataPoint, period int) ([]float64, error) {
+	sma, err := NewSMA(period)
+	if err != nil {
+		return nil, err
+	}
+	return batchCloses(points, sma.Update), nil
+}
+
+// EMA is an exponential moving average with a smoothing factor of 2/(period+1), seeded with the
+// simple average of the first period values.
+type EMA struct {
+	period int
+	alpha  float64
+	count  int
+	value  float64
+}
+
+// NewEMA returns an exponential moving average over period values.
+func NewEMA(period int) (*EMA, error) {
+	if err := checkPeriod("EMA", period); err != nil {
+		return nil, err
+	}
+	return newEMA(period, 2/float64(period+1)), nil
+}
+
+// newWilder returns the smoothing Welles Wilder used for RSI and ATR: an exponential moving
+// average with a smoothing factor of 1/period
+func newWilder(period int) *EMA {
+	return newEMA(period, 1/float64(period))
+}
+
+func newEMA(period int, alpha float64) *EMA {
+	return &EMA{period: period, alpha: alpha}
+}
+
+// Update adds a value and returns the average, which is ready once period values were added.
+func (e *EMA) Update(value float64) (float64, bool) {
+	if e.count < e.period {
+		e.count++
+		e.value += value
+		if e.count < e.period {
+			return math.NaN(), false
+		}
+		e.value /= float64(e.period)
+		return e.value, true
+	}
+
+	e.value += e.alpha * (value - e.value)
+	return e.value, true
+}
+
+// EMASeries returns the exponential moving average of the close prices of points.
+func EMASeries(points []laplace.PriceDataPoint, period int) ([]float64, error) {
+	ema, err := NewEMA(period)
+	if err != nil {
+		return nil, err
+	}
+	return batchCloses(points, ema.Update), nil
+}
+
+// WMA is a linearly weighted moving average: the last period values weighted 1 for the oldest
+// up to period for the newest.
+type WMA struct {
+	window *window
+}
+
+// NewWMA returns a weighted moving average over period values.
+func NewWMA(period int) (*WMA, error) {
+	if err := checkPeriod("WMA", period); err != nil {
+		return nil, err
+	}
+	return &WMA{window: newWindow(period)}, nil
+}
+
+// Update adds a value and returns the average, which is ready once period values were added.
+func (w *WMA) Update(value float64) (float64, bool) {
+	w.window.push(value)
+	if !w.window.full() {
+		return math.NaN(), false
+	}
+
+	period := len(w.window.values)
+	var sum float64
+	for i := 0; i < period; i++ {
+		sum += float64(i+1) * w.window.at(i)
+	}
+	return sum / float64(period*(period+1)/2), true
+}
+
+// WMASeries returns the weighted moving average of the close prices of points.
+func WMASeries(points []laplace.PriceDataPoint, period int) ([]float64, error) {
+	wma, err := NewWMA(period)
+	if err != nil {
+		return nil, err
+	}
+	return batchCloses(points, wma.Update), nil
+}
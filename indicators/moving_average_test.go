@@ -0,0 +1,79 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	laplace "github.com/Laplace-Analytics/laplace-api-golang"
+)
+
+func closePoints(closes ...float64) []laplace.PriceDataPoint {
+	points := make([]laplace.PriceDataPoint, len(closes))
+	for i, c := range closes {
+		points[i] = laplace.PriceDataPoint{Date: int64(i), Open: c, High: c, Low: c, Close: c}
+	}
+	return points
+}
+
+// requireSeries compares values, expecting NaN where want is NaN
+func requireSeries(t *testing.T, want, got []float64) {
+	t.Helper()
+	require.Len(t, got, len(want))
+	for i := range want {
+		if math.IsNaN(want[i]) {
+			require.True(t, math.IsNaN(got[i]), "value %d: %v is not NaN", i, got[i])
+			continue
+		}
+		require.InDelta(t, want[i], got[i], 1e-9, "value %d", i)
+	}
+}
+
+var nan = math.NaN()
+
+func TestSMA(t *testing.T) {
+	values, err := SMASeries(closePoints(1, 2, 3, 4, 5), 3)
+	require.NoError(t, err)
+	requireSeries(t, []float64{nan, nan, 2, 3, 4}, values)
+
+	sma, err := NewSMA(1)
+	require.NoError(t, err)
+	value, ok := sma.Update(7)
+	require.True(t, ok)
+	require.Equal(t, 7.0, value)
+
+	_, err = NewSMA(0)
+	require.ErrorContains(t, err, "invalid SMA period 0")
+}
+
+func TestEMA(t *testing.T) {
+	values, err := EMASeries(closePoints(2, 4, 6, 8, 12), 3)
+	require.NoError(t, err)
+	requireSeries(t, []float64{nan, nan, 4, 6, 9}, values)
+
+	_, err = EMASeries(nil, -1)
+	require.Error(t, err)
+}
+
+func TestWMA(t *testing.T) {
+	values, err := WMASeries(closePoints(1, 2, 3, 4, 6), 3)
+	require.NoError(t, err)
+	requireSeries(t, []float64{nan, nan, 14.0 / 6, 20.0 / 6, 29.0 / 6}, values)
+}
+
+func TestStreamingMatchesBatch(t *testing.T) {
+	points := closePoints(10, 11, 10.5, 12, 13, 12.5, 14, 13.5, 15, 16)
+	batchValues, err := EMASeries(points, 4)
+	require.NoError(t, err)
+
+	ema, err := NewEMA(4)
+	require.NoError(t, err)
+	for i, point := range points {
+		value, ok := ema.Update(point.Close)
+		require.Equal(t, i >= 3, ok)
+		if ok {
+			require.Equal(t, batchValues[i], value)
+		}
+	}
+}
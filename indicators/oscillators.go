@@ -0,0 +1,198 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+
+	laplace "github.com/Laplace-Analytics/laplace-api-golang"
+)
+
+// RSI is the relative strength index of Welles Wilder, between 0 and 100.
+type RSI struct {
+	gains    *EMA
+	losses   *EMA
+	previous float64
+	started  bool
+}
+
+// NewRSI returns a relative strength index over period changes, 14 being the usual choice.
+func NewRSI(period int) (*RSI, error) {
+	if err := checkPeriod("RSI", period); err != nil {
+		return nil, err
+	}
+	return &RSI{gains: newWilder(period), losses: newWilder(period)}, nil
+}
+
+// Update adds a value and returns the index, which is ready once period+1 values were added. A
+// series that did not move has an index of 50.
+func (r *RSI) Update(value float64) (float64, bool) {
+	if !r.started {
+		r.previous, r.started = value, true
+		return math.NaN(), false
+	}
+
+	change := value - r.previous
+	r.previous = value
+	gain, ok := r.gains.Update(math.Max(change, 0))
+	loss, _ := r.losses.Update(math.Max(-change, 0))
+	if !ok {
+		return math.NaN(), false
+	}
+
+	switch {
+	case loss == 0 && gain == 0:
+		return 50, true
+	case loss == 0:
+		return 100, true
+	}
+	return 100 - 100/(1+gain/loss), true
+}
+
+// RSISeries returns the relative strength index of the close prices of points.
+func RSISeries(points []laplace.PriceDataPoint, period int) ([]float64, error) {
+	rsi, err := NewRSI(period)
+	if err != nil {
+		return nil, err
+	}
+	return batchCloses(points, rsi.Update), nil
+}
+
+// MACDValue is a value of the moving average convergence divergence.
+type MACDValue struct {
+	// MACD is the fast EMA minus the slow EMA
+	MACD float64
+	// Signal is the EMA of MACD, NaN until enough MACD values were seen
+	Signal float64
+	// Histogram is MACD minus Signal
+	Histogram float64
+}
+
+// MACD is the moving average convergence divergence of Gerald Appel.
+type MACD struct {
+	fast   *EMA
+	slow   *EMA
+	signal *EMA
+}
+
+// NewMACD returns a moving average convergence divergence with the given EMA periods, 12, 26
+// and 9 being the usual choice.
+func NewMACD(fast, slow, signal int) (*MACD, error) {
+	for _, check := range []struct {
+		name   string
+		period int
+	}{{"MACD fast", fast}, {"MACD slow", slow}, {"MACD signal", signal}} {
+		if err := checkPeriod(check.name, check.period); err != nil {
+			return nil, err
+		}
+	}
+	if fast >= slow {
+		return nil, fmt.Errorf("MACD fast period %d is not shorter than slow period %d", fast, slow)
+	}
+
+	macd := &MACD{}
+	macd.fast, _ = NewEMA(fast)
+	macd.slow, _ = NewEMA(slow)
+	macd.signal, _ = NewEMA(signal)
+	return macd, nil
+}
+
+// Update adds a value and returns the indicator, which is ready once slow values were added.
+// The signal line follows signal-1 values later.
+func (m *MACD) Update(value float64) (MACDValue, bool) {
+	fast, _ := m.fast.Update(value)
+	slow, ok := m.slow.Update(value)
+	if !ok {
+		return missingMACD(), false
+	}
+
+	line := fast - slow
+	signal, ok := m.signal.Update(line)
+	if !ok {
+		return MACDValue{MACD: line, Signal: math.NaN(), Histogram: math.NaN()}, true
+	}
+	return MACDValue{MACD: line, Signal: signal, Histogram: line - signal}, true
+}
+
+func missingMACD() MACDValue {
+	return MACDValue{MACD: math.NaN(), Signal: math.NaN(), Histogram: math.NaN()}
+}
+
+// MACDSeries returns the moving average convergence divergence of the close prices of points.
+func MACDSeries(points []laplace.PriceDataPoint, fast, slow, signal int) ([]MACDValue, error) {
+	macd, err := NewMACD(fast, slow, signal)
+	if err != nil {
+		return nil, err
+	}
+	return batch(Closes(points), macd.Update, missingMACD()), nil
+}
+
+// StochasticValue is a value of the stochastic oscillator, between 0 and 100.
+type StochasticValue struct {
+	// K is where the close lies within the range of the last bars, smoothed
+	K float64
+	// D is the moving average of K, NaN until enough K values were seen
+	D float64
+}
+
+// Stochastic is the stochastic oscillator of George Lane.
+type Stochastic struct {
+	highs  *window
+	lows   *window
+	smooth *SMA
+	signal *SMA
+}
+
+// NewStochastic returns a stochastic oscillator over the range of period bars, with %K smoothed
+// over smoothK values and %D averaged over smoothD values of %K. A smoothK of 1 gives the fast
+// oscillator; 14, 3 and 3 give the usual slow one.
+func NewStochastic(period, smoothK, smoothD int) (*Stochastic, error) {
+	for _, check := range []struct {
+		name   string
+		period int
+	}{{"stochastic", period}, {"stochastic %K smoothing", smoothK}, {"stochastic %D", smoothD}} {
+		if err := checkPeriod(check.name, check.period); err != nil {
+			return nil, err
+		}
+	}
+
+	stochastic := &Stochastic{highs: newWindow(period), lows: newWindow(period)}
+	stochastic.smooth, _ = NewSMA(smoothK)
+	stochastic.signal, _ = NewSMA(smoothD)
+	return stochastic, nil
+}
+
+// Update adds a bar and returns the oscillator, which is ready once period+smoothK-1 bars were
+// added. A close within a range of zero width counts as the middle of the range.
+func (s *Stochastic) Update(point laplace.PriceDataPoint) (StochasticValue, bool) {
+	s.highs.push(point.High)
+	s.lows.push(point.Low)
+	if !s.highs.full() {
+		return missingStochastic(), false
+	}
+
+	high, low := s.highs.max(), s.lows.min()
+	raw := 50.0
+	if high > low {
+		raw = 100 * (point.Close - low) / (high - low)
+	}
+
+	k, ok := s.smooth.Update(raw)
+	if !ok {
+		return missingStochastic(), false
+	}
+	d, _ := s.signal.Update(k)
+	return StochasticValue{K: k, D: d}, true
+}
+
+func missingStochastic() StochasticValue {
+	return StochasticValue{K: math.NaN(), D: math.NaN()}
+}
+
+// StochasticSeries returns the stochastic oscillator of points.
+func StochasticSeries(points []laplace.PriceDataPoint, period, smoothK, smoothD int) ([]StochasticValue, error) {
+	stochastic, err := NewStochastic(period, smoothK, smoothD)
+	if err != nil {
+		return nil, err
+	}
+	return batch(points, stochastic.Update, missingStochastic()), nil
+}
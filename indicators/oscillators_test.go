@@ -0,0 +1,70 @@
+package indicators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	laplace "github.com/Laplace-Analytics/laplace-api-golang"
+)
+
+func TestRSI(t *testing.T) {
+	values, err := RSISeries(closePoints(1, 2, 1, 3, 3), 2)
+	require.NoError(t, err)
+	requireSeries(t, []float64{nan, nan, 50, 100 - 100.0/6, 100 - 100.0/6}, values)
+
+	rising, err := RSISeries(closePoints(1, 2, 3, 4), 2)
+	require.NoError(t, err)
+	requireSeries(t, []float64{nan, nan, 100, 100}, rising)
+
+	flat, err := RSISeries(closePoints(5, 5, 5), 2)
+	require.NoError(t, err)
+	requireSeries(t, []float64{nan, nan, 50}, flat)
+}
+
+func TestMACD(t *testing.T) {
+	values, err := MACDSeries(closePoints(1, 2, 4, 3, 5, 8, 7, 9), 2, 3, 2)
+	require.NoError(t, err)
+	require.Len(t, values, 8)
+
+	lines := make([]float64, len(values))
+	signals := make([]float64, len(values))
+	for i, value := range values {
+		lines[i], signals[i] = value.MACD, value.Signal
+		if i >= 3 {
+			require.InDelta(t, value.MACD-value.Signal, value.Histogram, 1e-9)
+		}
+	}
+	requireSeries(t, []float64{nan, nan, 0.833333333333333, 0.388888888888888, 0.518518518518518, 0.867283950617284, 0.469650205761317, 0.580161179698218}, lines)
+	requireSeries(t, []float64{nan, nan, nan, 0.611111111111111, 0.549382716049382, 0.761316872427983, 0.566872427983539, 0.575731595793325}, signals)
+
+	_, err = NewMACD(26, 12, 9)
+	require.ErrorContains(t, err, "not shorter")
+}
+
+func TestStochastic(t *testing.T) {
+	points := []laplace.PriceDataPoint{
+		{High: 5, Low: 3, Close: 4},
+		{High: 6, Low: 4, Close: 5},
+		{High: 7, Low: 5, Close: 7},
+		{High: 7, Low: 6, Close: 6},
+		{High: 6, Low: 6, Close: 6},
+	}
+	values, err := StochasticSeries(points, 3, 1, 2)
+	require.NoError(t, err)
+
+	k := make([]float64, len(values))
+	d := make([]float64, len(values))
+	for i, value := range values {
+		k[i], d[i] = value.K, value.D
+	}
+	requireSeries(t, []float64{nan, nan, 100, 200.0 / 3, 50}, k)
+	requireSeries(t, []float64{nan, nan, nan, 250.0 / 3, 175.0 / 3}, d)
+
+	flat, err := StochasticSeries(closePoints(5, 5, 5), 3, 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, 50.0, flat[2].K)
+
+	_, err = NewStochastic(14, 0, 3)
+	require.ErrorContains(t, err, "invalid stochastic %K smoothing period 0")
+}
@@ -0,0 +1,67 @@
+package indicators
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	laplace "github.com/Laplace-Analytics/laplace-api-golang"
+)
+
+type ScreenerTestSuite struct {
+	*laplace.ClientTestSuite
+}
+
+func TestScreener(t *testing.T) {
+	suite.Run(t, &ScreenerTestSuite{
+		laplace.NewClientTestSuite(),
+	})
+}
+
+// TestSMAMatchesScreener checks the moving averages computed from daily bars against the ones
+// the screener reports. The screener may not include the current session yet, so the average
+// as of either of the last two bars is accepted.
+func (s *ScreenerTestSuite) TestSMAMatchesScreener() {
+	client, err := laplace.NewClient(s.Config)
+	s.Require().NoError(err)
+
+	ctx := context.Background()
+
+	resp, err := client.Screener(ctx, laplace.RegionTr, laplace.ScreenerRequest{
+		SortBy:    laplace.ScreenerSortByMarketCap,
+		SortOrder: laplace.SortDirectionDesc,
+		Page:      1,
+		PageSize:  5,
+	})
+	s.Require().NoError(err)
+	s.Require().NotEmpty(resp.Items)
+
+	to := time.Now()
+	from := to.AddDate(-1, -6, 0)
+	for _, item := range resp.Items {
+		points, err := client.GetCustomHistoricalPricesBetween(ctx, item.Symbol, laplace.RegionTr, from, to, laplace.HistoricalPriceIntervalOneDay, false)
+		s.Require().NoError(err)
+
+		for _, check := range []struct {
+			period   int
+			screener *float64
+		}{{20, item.SMA20}, {50, item.SMA50}, {150, item.SMA150}, {200, item.SMA200}} {
+			if check.screener == nil || len(points) < check.period+1 {
+				continue
+			}
+
+			values, err := SMASeries(points, check.period)
+			s.Require().NoError(err)
+
+			last, previous := values[len(values)-1], values[len(values)-2]
+			matches := func(value float64) bool {
+				return math.Abs(value-*check.screener) <= 0.005*math.Abs(*check.screener)
+			}
+			s.Require().True(matches(last) || matches(previous),
+				"%s SMA%d: screener %f, computed %f and %f", item.Symbol, check.period, *check.screener, last, previous)
+		}
+	}
+}
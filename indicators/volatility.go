@@ -0,0 +1,107 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+
+	laplace "github.com/Laplace-Analytics/laplace-api-golang"
+)
+
+// BollingerValue is a value of the Bollinger Bands.
+type BollingerValue struct {
+	// Middle is the simple moving average
+	Middle float64
+	// Upper and Lower lie the multiplier times the standard deviation above and below Middle
+	Upper float64
+	Lower float64
+}
+
+// BollingerBands are the Bollinger Bands of John Bollinger.
+type BollingerBands struct {
+	window     *window
+	multiplier float64
+}
+
+// NewBollingerBands returns Bollinger Bands over period values, multiplier standard deviations
+// wide; 20 and 2 are the usual choice. The standard deviation is that of the population, as
+// Bollinger defined it.
+func NewBollingerBands(period int, multiplier float64) (*BollingerBands, error) {
+	if err := checkPeriod("Bollinger Bands", period); err != nil {
+		return nil, err
+	}
+	if multiplier <= 0 {
+		return nil, fmt.Errorf("invalid Bollinger Bands multiplier %g", multiplier)
+	}
+	return &BollingerBands{window: newWindow(period), multiplier: multiplier}, nil
+}
+
+// Update adds a value and returns the bands, which are ready once period values were added.
+func (b *BollingerBands) Update(value float64) (BollingerValue, bool) {
+	b.window.push(value)
+	if !b.window.full() {
+		return missingBollinger(), false
+	}
+
+	period := len(b.window.values)
+	var sum float64
+	for i := 0; i < period; i++ {
+		sum += b.window.at(i)
+	}
+	mean := sum / float64(period)
+
+	var squares float64
+	for i := 0; i < period; i++ {
+		squares += (b.window.at(i) - mean) * (b.window.at(i) - mean)
+	}
+	width := b.multiplier * math.Sqrt(squares/float64(period))
+
+	return BollingerValue{Middle: mean, Upper: mean + width, Lower: mean - width}, true
+}
+
+func missingBollinger() BollingerValue {
+	return BollingerValue{Middle: math.NaN(), Upper: math.NaN(), Lower: math.NaN()}
+}
+
+// BollingerSeries returns the Bollinger Bands of the close prices of points.
+func BollingerSeries(points []laplace.PriceDataPoint, period int, multiplier float64) ([]BollingerValue, error) {
+	bands, err := NewBollingerBands(period, multiplier)
+	if err != nil {
+		return nil, err
+	}
+	return batch(Closes(points), bands.Update, missingBollinger()), nil
+}
+
+// ATR is the average true range of Welles Wilder.
+type ATR struct {
+	average  *EMA
+	previous float64
+	started  bool
+}
+
+// NewATR returns an average true range over period bars, 14 being the usual choice.
+func NewATR(period int) (*ATR, error) {
+	if err := checkPeriod("ATR", period); err != nil {
+		return nil, err
+	}
+	return &ATR{average: newWilder(period)}, nil
+}
+
+// Update adds a bar and returns the average, which is ready once period bars were added. The
+// true range of the first bar is its high minus its low, as there is no previous close.
+func (a *ATR) Update(point laplace.PriceDataPoint) (float64, bool) {
+	trueRange := point.High - point.Low
+	if a.started {
+		trueRange = math.Max(trueRange, math.Max(math.Abs(point.High-a.previous), math.Abs(point.Low-a.previous)))
+	}
+	a.previous, a.started = point.Close, true
+	return a.average.Update(trueRange)
+}
+
+// ATRSeries returns the average true range of points.
+func ATRSeries(points []laplace.PriceDataPoint, period int) ([]float64, error) {
+	atr, err := NewATR(period)
+	if err != nil {
+		return nil, err
+	}
+	return batch(points, atr.Update, math.NaN()), nil
+}
@@ -0,0 +1,37 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	laplace "github.com/Laplace-Analytics/laplace-api-golang"
+)
+
+func TestBollingerBands(t *testing.T) {
+	values, err := BollingerSeries(closePoints(1, 2, 3, 3), 3, 2)
+	require.NoError(t, err)
+	require.True(t, math.IsNaN(values[1].Middle))
+
+	width := 2 * math.Sqrt(2.0/3)
+	require.InDelta(t, 2, values[2].Middle, 1e-9)
+	require.InDelta(t, 2+width, values[2].Upper, 1e-9)
+	require.InDelta(t, 2-width, values[2].Lower, 1e-9)
+
+	_, err = NewBollingerBands(20, 0)
+	require.ErrorContains(t, err, "invalid Bollinger Bands multiplier")
+}
+
+func TestATR(t *testing.T) {
+	points := []laplace.PriceDataPoint{
+		{High: 10, Low: 8, Close: 9},
+		{High: 11, Low: 9, Close: 10},
+		{High: 12, Low: 9, Close: 11},
+		// Gaps up, so the true range reaches back to the previous close
+		{High: 15, Low: 14, Close: 14.5},
+	}
+	values, err := ATRSeries(points, 2)
+	require.NoError(t, err)
+	requireSeries(t, []float64{nan, 2, 2.5, 3.25}, values)
+}
@@ -0,0 +1,84 @@
+package indicators
+
+import (
+	"math"
+	"time"
+
+	laplace "github.com/Laplace-Analytics/laplace-api-golang"
+)
+
+// OBV is the on-balance volume of Joseph Granville: the running sum of the volume of bars that
+// closed up, minus that of bars that closed down.
+type OBV struct {
+	value    float64
+	previous float64
+	started  bool
+}
+
+// NewOBV returns an on-balance volume starting at zero.
+func NewOBV() *OBV {
+	return &OBV{}
+}
+
+// Update adds a bar and returns the on-balance volume, which is always ready.
+func (o *OBV) Update(point laplace.PriceDataPoint) (float64, bool) {
+	if o.started {
+		switch {
+		case point.Close > o.previous:
+			o.value += point.Volume
+		case point.Close < o.previous:
+			o.value -= point.Volume
+		}
+	}
+	o.previous, o.started = point.Close, true
+	return o.value, true
+}
+
+// OBVSeries returns the on-balance volume of points.
+func OBVSeries(points []laplace.PriceDataPoint) []float64 {
+	return batch(points, NewOBV().Update, math.NaN())
+}
+
+// VWAP is the volume-weighted average price of the bars since it was created or last reset,
+// weighting the typical price (high+low+close)/3 of each bar by its volume.
+type VWAP struct {
+	value  float64
+	volume float64
+}
+
+// NewVWAP returns a volume-weighted average price.
+func NewVWAP() *VWAP {
+	return &VWAP{}
+}
+
+// Reset starts a new average, typically at the open of a session.
+func (v *VWAP) Reset() {
+	v.value, v.volume = 0, 0
+}
+
+// Update adds a bar and returns the average, which is ready once a bar with volume was added.
+func (v *VWAP) Update(point laplace.PriceDataPoint) (float64, bool) {
+	v.value += (point.High + point.Low + point.Close) / 3 * point.Volume
+	v.volume += point.Volume
+	if v.volume == 0 {
+		return math.NaN(), false
+	}
+	return v.value / v.volume, true
+}
+
+// VWAPSeries returns the volume-weighted average price of intraday bars, starting a new average
+// on every calendar day in location, such as that of Region.Location. With a nil location, one
+// average runs over the whole series.
+func VWAPSeries(points []laplace.PriceDataPoint, location *time.Location) []float64 {
+	vwap := NewVWAP()
+	var day string
+	return batch(points, func(point laplace.PriceDataPoint) (float64, bool) {
+		if location != nil {
+			if date := point.Time().In(location).Format(time.DateOnly); date != day {
+				vwap.Reset()
+				day = date
+			}
+		}
+		return vwap.Update(point)
+	}, math.NaN())
+}
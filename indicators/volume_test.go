@@ -0,0 +1,34 @@
+package indicators
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	laplace "github.com/Laplace-Analytics/laplace-api-golang"
+)
+
+func TestOBV(t *testing.T) {
+	points := closePoints(10, 11, 10, 10, 12)
+	for i := range points {
+		points[i].Volume = float64(100 * (i + 1))
+	}
+	require.Equal(t, []float64{0, 200, -100, -100, 400}, OBVSeries(points))
+}
+
+func TestVWAP(t *testing.T) {
+	location := laplace.RegionTr.Location()
+	at := func(day, hour int) int64 {
+		return time.Date(2025, 3, day, hour, 0, 0, 0, location).UnixMilli()
+	}
+	points := []laplace.PriceDataPoint{
+		{Date: at(3, 10), High: 11, Low: 9, Close: 10, Volume: 100},
+		{Date: at(3, 11), High: 14, Low: 10, Close: 12, Volume: 300},
+		{Date: at(4, 10), High: 20, Low: 20, Close: 20, Volume: 0},
+		{Date: at(4, 11), High: 22, Low: 18, Close: 20, Volume: 50},
+	}
+
+	requireSeries(t, []float64{10, 11.5, nan, 20}, VWAPSeries(points, location))
+	requireSeries(t, []float64{10, 11.5, 11.5, (10*100 + 12*300 + 20*50) / 450.0}, VWAPSeries(points, nil))
+}
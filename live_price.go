@@ -2,9 +2,12 @@ package laplace
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -71,7 +74,7 @@ type LivePriceStream[T any] struct {
 	mu           sync.RWMutex
 	ctx          context.Context
 	cancel       context.CancelFunc
-	sseChan      <-chan LivePriceResult[T]
+	streamCtx    context.Context
 	outputChan   chan LivePriceResult[T]
 	c            *Client
 	region       Region
@@ -79,18 +82,132 @@ type LivePriceStream[T any] struct {
 	symbols      []string
 	closed       bool
 	isSubscribed bool
+
+	// observers has a lock of its own so that forwarders never wait on mu, which Close holds
+	// while it waits for them. The slice is replaced rather than modified in place, so
+	// forwarders can range over a snapshot; entries are pointers so they can be told apart.
+	observerMu sync.RWMutex
+	observers  []*liveObserver[T]
+
+	// forwarders counts the goroutines sending to outputChan, which is only closed once they
+	// have all returned
+	forwarders sync.WaitGroup
+
+	// fallback enables dropping to the delayed feed when the live feed is not entitled;
+	// upgradeInterval is how often an upgrade back to live is attempted while delayed.
+	fallback        bool
+	upgradeInterval time.Duration
+	delayed         bool
+
+	// shards holds one SSE connection per group of at most shardSize symbols; generation is
+	// bumped whenever the shard layout changes.
+	shards     []*liveShard[T]
+	shardSize  int
+	generation int
+
+	// pool, when set, recycles decoded events; consumers return them with Release
+	pool *eventPool[T]
 }
 
+// DefaultLiveUpgradeInterval is how often a stream that fell back to delayed prices retries the
+// live feed.
+const DefaultLiveUpgradeInterval = 5 * time.Minute
+
+// liveObserver is notified of every result forwarded by a LivePriceStream together with the
+// time it was received.
+type liveObserver[T any] func(result LivePriceResult[T], receivedAt time.Time)
+
 // NewLivePriceStream creates a new LivePriceStream
 func NewLivePriceStream[T any](client *Client, priceType LivePriceType, region Region) *LivePriceStream[T] {
-	return &LivePriceStream[T]{
-		c:         client,
-		priceType: priceType,
-		region:    region,
-		closed:    false,
+	s := &LivePriceStream[T]{
+		c:               client,
+		priceType:       priceType,
+		region:          region,
+		closed:          false,
+		fallback:        true,
+		upgradeInterval: DefaultLiveUpgradeInterval,
+		shardSize:       DefaultLiveShardSize,
+	}
+
+	if client.metrics != nil {
+		s.addObserver(s.observeMetrics)
+	}
+
+	return s
+}
+
+// observeMetrics reports event, latency and decode error telemetry for a forwarded result
+func (s *LivePriceStream[T]) observeMetrics(result LivePriceResult[T], receivedAt time.Time) {
+	name := liveStreamName(s.priceType, s.region)
+
+	if result.Error != nil {
+		if IsRecoverable(result.Error) {
+			s.c.metrics.IncDecodeErrors(name)
+		}
+		return
+	}
+
+	s.c.metrics.IncEvents(name)
+	if tick, ok := any(result.Data).(liveTick); ok {
+		if date := liveDateToTime(tick.tickDate()); !date.IsZero() {
+			s.c.metrics.ObserveLatency(name, receivedAt.Sub(date))
+		}
+	}
+}
+
+// recordReconnects reports n connections re-established after the initial subscription
+func (s *LivePriceStream[T]) recordReconnects(n int) {
+	if s.c.metrics == nil {
+		return
+	}
+	for i := 0; i < n; i++ {
+		s.c.metrics.IncReconnects(liveStreamName(s.priceType, s.region))
+	}
+}
+
+// recordDropped reports an event that was received but not delivered, and releases it
+func (s *LivePriceStream[T]) recordDropped(data LivePriceResult[T]) {
+	data.Release()
+	if s.c.metrics != nil {
+		s.c.metrics.IncDropped(liveStreamName(s.priceType, s.region))
 	}
 }
 
+// SetDelayedFallback configures whether a BIST live price stream drops to delayed prices when
+// the live feed is not entitled, and how often it tries to upgrade back to live. It is enabled
+// by default and takes effect on the next Subscribe.
+func (s *LivePriceStream[T]) SetDelayedFallback(enabled bool, upgradeInterval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fallback = enabled
+	if upgradeInterval > 0 {
+		s.upgradeInterval = upgradeInterval
+	}
+}
+
+// SetPooledEvents enables decoding events into pooled objects to cut allocations on high-volume
+// streams. Consumers must then call Release on every received result once done with its Data.
+// It takes effect on the next Subscribe or symbol change.
+func (s *LivePriceStream[T]) SetPooledEvents(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !enabled {
+		s.pool = nil
+	} else if s.pool == nil {
+		s.pool = newEventPool[T]()
+	}
+}
+
+// IsDelayed reports whether the stream is currently serving delayed prices
+func (s *LivePriceStream[T]) IsDelayed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.delayed
+}
+
 // Subscribe subscribes to live price updates for given symbols
 func (s *LivePriceStream[T]) Subscribe(ctx context.Context, symbols []string) error {
 	if ctx == nil {
@@ -100,6 +217,8 @@ func (s *LivePriceStream[T]) Subscribe(ctx context.Context, symbols []string) er
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	resubscribe := len(s.shards) > 0
+
 	// Cleanup existing stream
 	if err := s.cleanupExistingStream(); err != nil {
 		return fmt.Errorf("failed to cleanup existing stream: %w", err)
@@ -115,6 +234,10 @@ func (s *LivePriceStream[T]) Subscribe(ctx context.Context, symbols []string) er
 		return fmt.Errorf("failed to start streaming: %w", err)
 	}
 
+	if resubscribe {
+		s.recordReconnects(len(s.shards))
+	}
+
 	s.isSubscribed = true
 	return nil
 }
@@ -148,13 +271,46 @@ func (s *LivePriceStream[T]) Close() error {
 	return s.cleanupExistingStream()
 }
 
+// Symbols returns the symbols the stream is currently subscribed to
+func (s *LivePriceStream[T]) Symbols() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]string(nil), s.symbols...)
+}
+
+// addObserver registers fn to be called for every result the stream forwards and returns a func
+// that unregisters it
+func (s *LivePriceStream[T]) addObserver(fn liveObserver[T]) (remove func()) {
+	s.observerMu.Lock()
+	defer s.observerMu.Unlock()
+
+	observer := &fn
+	s.observers = append(slices.Clip(s.observers), observer)
+
+	return func() {
+		s.observerMu.Lock()
+		defer s.observerMu.Unlock()
+
+		s.observers = slices.DeleteFunc(slices.Clone(s.observers), func(o *liveObserver[T]) bool {
+			return o == observer
+		})
+	}
+}
+
 // cleanupExistingStream cancels and cleans up existing streaming task
 func (s *LivePriceStream[T]) cleanupExistingStream() error {
 	if s.cancel != nil {
 		s.cancel()
 		s.cancel = nil
+		s.streamCtx = nil
+		s.shards = nil
+		s.generation++
 	}
 
+	// Every forwarder has been cancelled above; none may be sending when the channel closes
+	s.forwarders.Wait()
+
 	if s.outputChan != nil {
 		close(s.outputChan)
 		s.outputChan = nil
@@ -163,22 +319,22 @@ func (s *LivePriceStream[T]) cleanupExistingStream() error {
 	return nil
 }
 
-// buildStreamURL builds the streaming URL for the given symbols and region
-func (s *LivePriceStream[T]) buildStreamURL() string {
+// buildStreamURL builds the streaming URL for the given price type, symbols and region
+func (s *LivePriceStream[T]) buildStreamURL(priceType LivePriceType, symbols []string) string {
 	streamID := uuid.New().String()
-	symbolsParam := strings.Join(s.symbols, ",")
+	symbolsParam := strings.Join(symbols, ",")
 
 	baseURL := s.c.baseUrl
 	var endpoint string
 
 	switch {
-	case s.priceType == LivePriceTypePrice && s.region == RegionTr:
+	case priceType == LivePriceTypePrice && s.region == RegionTr:
 		endpoint = "/api/v2/stock/price/live"
-	case s.priceType == LivePriceTypeDelayedPrice:
+	case priceType == LivePriceTypeDelayedPrice:
 		endpoint = "/api/v1/stock/price/delayed"
-	case s.priceType == LivePriceTypeOrderBook:
+	case priceType == LivePriceTypeOrderBook:
 		endpoint = "/api/v1/stock/orderbook/live"
-	case s.priceType == LivePriceTypeBidAsk:
+	case priceType == LivePriceTypeBidAsk:
 		endpoint = "/api/v1/stock/price/bids"
 	default:
 		endpoint = "/api/v2/stock/price/live"
@@ -188,59 +344,214 @@ func (s *LivePriceStream[T]) buildStreamURL() string {
 		baseURL, endpoint, symbolsParam, string(s.region), streamID)
 }
 
-// startStreaming starts the SSE streaming connection
-func (s *LivePriceStream[T]) startStreaming() error {
-	url := s.buildStreamURL()
+// canFallback reports whether the stream may drop to delayed prices
+func (s *LivePriceStream[T]) canFallback() bool {
+	return s.fallback && s.priceType == LivePriceTypePrice && s.region == RegionTr
+}
 
+// effectivePriceType returns the price type connections are currently opened with
+func (s *LivePriceStream[T]) effectivePriceType() LivePriceType {
+	if s.delayed {
+		return LivePriceTypeDelayedPrice
+	}
+	return s.priceType
+}
+
+// isEntitlementError reports whether err means the user lacks access to the live feed
+func isEntitlementError(err error) bool {
+	return errors.Is(err, ErrYouDoNotHaveAccessToEndpoint) || errors.Is(err, ErrNoAccessToLevel)
+}
+
+// connect opens an SSE connection to url. The returned cancel func closes only this connection.
+func (s *LivePriceStream[T]) connect(ctx context.Context, url string) (<-chan LivePriceResult[T], context.CancelFunc, error) {
+	connCtx, connCancel := context.WithCancel(ctx)
+
+	channel, _, err := sendSSERequest[T](connCtx, s.c, url, s.pool)
+	if err != nil {
+		connCancel()
+		return nil, nil, err
+	}
+
+	return channel, connCancel, nil
+}
+
+// startStreaming starts the SSE streaming connections
+func (s *LivePriceStream[T]) startStreaming() error {
 	ctxWithCancel, cancel := context.WithCancel(s.ctx)
 	s.cancel = cancel
+	s.streamCtx = ctxWithCancel
 
-	channel, _, err := sendSSERequest[T](ctxWithCancel, s.c, url)
+	groups := shardSymbols(s.symbols, s.shardSize)
+
+	priceType := s.priceType
+	shards, err := s.openShards(ctxWithCancel, priceType, groups)
+	if err != nil && s.canFallback() && isEntitlementError(err) {
+		s.c.logger.Infof("no live price entitlement, falling back to delayed prices: %v", err)
+
+		priceType = LivePriceTypeDelayedPrice
+		shards, err = s.openShards(ctxWithCancel, priceType, groups)
+		if err == nil {
+			go s.upgradeToLive(ctxWithCancel, s.upgradeInterval)
+		}
+	}
 	if err != nil {
 		return fmt.Errorf("failed to establish SSE connection: %w", err)
 	}
 
-	s.sseChan = channel
-	go s.forwardData()
+	s.shards = shards
+	s.generation++
+	s.delayed = priceType == LivePriceTypeDelayedPrice
+	s.runShards(ctxWithCancel, shards)
 
 	return nil
 }
 
-// forwardData forwards data from SSE channel to output channel
-func (s *LivePriceStream[T]) forwardData() {
-	defer func() {
-		if r := recover(); r != nil {
-			s.c.logger.Error("panic in forwardData", r)
+// upgradeToLive periodically retries the live feed while the stream is on delayed prices and
+// swaps connections once the live feed is available again.
+func (s *LivePriceStream[T]) upgradeToLive(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.RLock()
+		generation := s.generation
+		groups := shardGroups(s.shards)
+		s.mu.RUnlock()
+
+		shards, err := s.openShards(ctx, s.priceType, groups)
+		if err != nil {
+			if !isEntitlementError(err) {
+				s.c.logger.Warnf("failed to upgrade to live prices: %v", err)
+			}
+			continue
+		}
+
+		s.mu.Lock()
+		if ctx.Err() != nil || generation != s.generation {
+			// The stream was closed or its symbols changed while connecting; retry on the next tick
+			s.mu.Unlock()
+			closeShards(shards)
+			continue
 		}
-	}()
+		previous := s.shards
+		s.shards = shards
+		s.generation++
+		s.delayed = false
+		s.runShards(ctx, shards)
+		s.recordReconnects(len(shards))
+		s.mu.Unlock()
+
+		closeShards(previous)
+		s.c.logger.Info("live price entitlement available again, upgraded to live prices")
+		return
+	}
+}
 
+// forwardData forwards data from the SSE channel of the connection streaming symbols to the
+// output channel. The terminal error of the connection is reported as a LiveShardError.
+func (s *LivePriceStream[T]) forwardData(ctx context.Context, sseChan <-chan LivePriceResult[T], symbols []string, outputChan chan<- LivePriceResult[T], delayed bool) {
 	for {
 		select {
-		case data, ok := <-s.sseChan:
+		case data, ok := <-sseChan:
 			if !ok {
 				return
 			}
 
-			s.mu.RLock()
-			outputChan := s.outputChan
-			closed := s.closed
-			s.mu.RUnlock()
+			receivedAt := time.Now()
+			data.Delayed = delayed
+			if data.Error != nil && !IsRecoverable(data.Error) {
+				data.Error = &LiveShardError{Symbols: symbols, Err: data.Error}
+			}
 
-			if closed || outputChan == nil {
-				return
+			s.observerMu.RLock()
+			observers := s.observers
+			s.observerMu.RUnlock()
+
+			for _, observe := range observers {
+				(*observe)(data, receivedAt)
 			}
 
 			select {
 			case outputChan <- data:
-			case <-s.ctx.Done():
+			case <-ctx.Done():
+				s.recordDropped(data)
 				return
 			}
-		case <-s.ctx.Done():
+		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+// liveTick is implemented by live stream payloads that carry a symbol and an update time.
+// An empty symbol marks a feed-level message such as a heartbeat.
+type liveTick interface {
+	tickSymbol() string
+	tickDate() int64
+}
+
+// livePriceTick is implemented by live payloads that carry a price; 0 means no price
+type livePriceTick interface {
+	liveTick
+	tickPrice() float64
+}
+
+// liveDateToTime converts the `d` field of live messages to a time.Time. The field is sent
+// in epoch milliseconds; epoch seconds are recognized as PriceDataPoint dates are. A missing
+// date is the zero time.
+func liveDateToTime(d int64) time.Time {
+	if d <= 0 {
+		return time.Time{}
+	}
+	return unixTime(d)
+}
+
+func (m LiveMessageV2[T]) tickSymbol() string {
+	if m.Type == MessageTypeHeartbeat {
+		return ""
+	}
+	if m.Symbol != "" {
+		return m.Symbol
+	}
+	if t, ok := any(m.Data).(liveTick); ok {
+		return t.tickSymbol()
+	}
+	return ""
+}
+
+func (m LiveMessageV2[T]) tickDate() int64 {
+	if t, ok := any(m.Data).(liveTick); ok {
+		return t.tickDate()
+	}
+	return 0
+}
+
+func (m LiveMessageV2[T]) tickPrice() float64 {
+	if t, ok := any(m.Data).(livePriceTick); ok {
+		return t.tickPrice()
+	}
+	return 0
+}
+
+// reset clears the order book update for reuse while keeping the level slices' capacity. The
+// spare capacity is zeroed too, as the decoder fills it in place.
+func (d *BISTStockOrderBookData) reset() {
+	clear(d.Updated[:cap(d.Updated)])
+	clear(d.Deleted[:cap(d.Deleted)])
+	d.Updated = d.Updated[:0]
+	d.Deleted = d.Deleted[:0]
+	d.Symbol = ""
+}
+
+func (d BISTStockOrderBookData) tickSymbol() string { return d.Symbol }
+func (d BISTStockOrderBookData) tickDate() int64    { return 0 }
+
 type BISTStockLiveData struct {
 	Symbol             string  `json:"s"`
 	DailyPercentChange float64 `json:"ch"`
@@ -256,6 +567,14 @@ type USStockLiveData struct {
 	AmountChange  float64 `json:"ac"`
 }
 
+func (d BISTStockLiveData) tickSymbol() string { return d.Symbol }
+func (d BISTStockLiveData) tickDate() int64    { return d.Date }
+func (d BISTStockLiveData) tickPrice() float64 { return d.ClosePrice }
+
+func (d USStockLiveData) tickSymbol() string { return d.Symbol }
+func (d USStockLiveData) tickDate() int64    { return d.Date }
+func (d USStockLiveData) tickPrice() float64 { return d.Price }
+
 // ===== NEW UNIFIED STREAMING API =====
 
 // GetLivePriceStreamForBIST creates a new live price stream for BIST stocks.
@@ -336,6 +655,23 @@ type BISTBidAskLiveData struct {
 	Date   int64   `json:"d"`
 }
 
+func (r BISTBidAskResponse) tickSymbol() string {
+	if r.Type == MessageTypeHeartbeat {
+		return ""
+	}
+	return r.Data.Symbol
+}
+
+func (r BISTBidAskResponse) tickDate() int64 { return r.Data.Date }
+
+// tickPrice is the mid price, or 0 while either side of the quote is missing
+func (r BISTBidAskResponse) tickPrice() float64 {
+	if r.Data.Ask <= 0 || r.Data.Bid <= 0 {
+		return 0
+	}
+	return (r.Data.Ask + r.Data.Bid) / 2
+}
+
 // GetLiveBidAskStreamForBIST creates a new bid/ask price stream for BIST stocks.
 // Call Subscribe(ctx, symbols) on the returned stream to start receiving data.
 // Passing no symbols to Subscribe means all BIST stocks will be streamed.
@@ -352,4 +688,3 @@ func (c *Client) CreateLiveBidAskStreamForBIST(ctx context.Context, symbols []st
 	}
 	return stream, nil
 }
-
@@ -0,0 +1,71 @@
+package laplace
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// LivePriceQuote is the latest price a LivePriceCache has seen for a symbol.
+type LivePriceQuote struct {
+	Price float64
+	// UpdatedAt is the time of the update carried by the stream, or the receive time when
+	// the update has none
+	UpdatedAt time.Time
+}
+
+// LivePriceCache keeps the latest price of every symbol a live price stream delivers. It can
+// be used as a NewsPriceSource to enrich news with prices.
+type LivePriceCache struct {
+	mu     sync.RWMutex
+	quotes map[string]LivePriceQuote
+}
+
+// NewPriceCache creates a LivePriceCache fed by the stream. Streams whose payload carries no
+// price, such as order book streams, leave the cache empty.
+func (s *LivePriceStream[T]) NewPriceCache() *LivePriceCache {
+	cache := &LivePriceCache{quotes: make(map[string]LivePriceQuote)}
+
+	s.addObserver(func(result LivePriceResult[T], receivedAt time.Time) {
+		if result.Error != nil {
+			return
+		}
+		if tick, ok := any(result.Data).(livePriceTick); ok {
+			cache.update(tick, receivedAt)
+		}
+	})
+
+	return cache
+}
+
+func (c *LivePriceCache) update(tick livePriceTick, receivedAt time.Time) {
+	symbol, price := tick.tickSymbol(), tick.tickPrice()
+	if symbol == "" || price == 0 {
+		return
+	}
+
+	updatedAt := liveDateToTime(tick.tickDate())
+	if updatedAt.IsZero() {
+		updatedAt = receivedAt
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.quotes[strings.ToUpper(symbol)] = LivePriceQuote{Price: price, UpdatedAt: updatedAt}
+}
+
+// Quote returns the latest quote of symbol, if the stream delivered one.
+func (c *LivePriceCache) Quote(symbol string) (LivePriceQuote, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	quote, ok := c.quotes[strings.ToUpper(symbol)]
+	return quote, ok
+}
+
+// LatestPrice returns the latest price of symbol, if the stream delivered one.
+func (c *LivePriceCache) LatestPrice(symbol string) (float64, bool) {
+	quote, ok := c.Quote(symbol)
+	return quote.Price, ok
+}
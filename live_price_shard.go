@@ -0,0 +1,236 @@
+package laplace
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// DefaultLiveShardSize is the maximum number of symbols streamed over a single SSE connection.
+// Larger symbol sets are split over several connections to stay clear of URL length limits.
+const DefaultLiveShardSize = 100
+
+// liveShard is a single SSE connection streaming a subset of the stream's symbols
+type liveShard[T any] struct {
+	symbols []string
+	channel <-chan LivePriceResult[T]
+	cancel  context.CancelFunc
+}
+
+// SetShardSize sets the maximum number of symbols per connection. It takes effect on the next
+// Subscribe or symbol change.
+func (s *LivePriceStream[T]) SetShardSize(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if size > 0 {
+		s.shardSize = size
+	}
+}
+
+// AddSymbols adds symbols to a subscribed stream. Only connections whose symbol set changes
+// are reopened; the output channel stays the same. A stream of every symbol already streams
+// them, so it is left as it is.
+func (s *LivePriceStream[T]) AddSymbols(symbols ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isSubscribed {
+		return fmt.Errorf("stream is not subscribed")
+	}
+	if len(s.symbols) == 0 {
+		return nil
+	}
+
+	next := slices.Clone(s.symbols)
+	for _, symbol := range symbols {
+		if !slices.Contains(next, symbol) {
+			next = append(next, symbol)
+		}
+	}
+
+	return s.rebalance(next)
+}
+
+// RemoveSymbols removes symbols from a subscribed stream. Only connections whose symbol set
+// changes are reopened or closed; the output channel stays the same.
+func (s *LivePriceStream[T]) RemoveSymbols(symbols ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isSubscribed {
+		return fmt.Errorf("stream is not subscribed")
+	}
+	if len(s.symbols) == 0 {
+		return fmt.Errorf("cannot remove symbols from a stream of every symbol, subscribe to the wanted symbols instead")
+	}
+
+	next := slices.DeleteFunc(slices.Clone(s.symbols), func(symbol string) bool {
+		return slices.Contains(symbols, symbol)
+	})
+	if len(next) == 0 {
+		// An empty filter streams every symbol, which is not what removing symbols means
+		return fmt.Errorf("cannot remove every symbol from the stream, use Close instead")
+	}
+
+	return s.rebalance(next)
+}
+
+// rebalance moves the stream to the given symbol set. New connections are opened before old
+// ones are closed, and the layout is left untouched if any connection fails.
+func (s *LivePriceStream[T]) rebalance(symbols []string) error {
+	groups, reuse := planShards(shardGroups(s.shards), symbols, s.shardSize)
+
+	priceType := s.effectivePriceType()
+	next := make([]*liveShard[T], len(groups))
+	var opened []*liveShard[T]
+	for i, group := range groups {
+		if reuse[i] >= 0 {
+			next[i] = s.shards[reuse[i]]
+			continue
+		}
+
+		shards, err := s.openShards(s.streamCtx, priceType, [][]string{group})
+		if err != nil {
+			closeShards(opened)
+			return fmt.Errorf("failed to rebalance stream: %w", err)
+		}
+		next[i] = shards[0]
+		opened = append(opened, shards[0])
+	}
+
+	var stale []*liveShard[T]
+	for _, shard := range s.shards {
+		if !slices.Contains(next, shard) {
+			stale = append(stale, shard)
+		}
+	}
+
+	s.symbols = symbols
+	s.shards = next
+	s.generation++
+	s.runShards(s.streamCtx, opened)
+	s.recordReconnects(len(opened))
+	closeShards(stale)
+
+	return nil
+}
+
+// openShards connects one shard per symbol group, closing all of them if any fails
+func (s *LivePriceStream[T]) openShards(ctx context.Context, priceType LivePriceType, groups [][]string) ([]*liveShard[T], error) {
+	shards := make([]*liveShard[T], 0, len(groups))
+	for _, symbols := range groups {
+		channel, cancel, err := s.connect(ctx, s.buildStreamURL(priceType, symbols))
+		if err != nil {
+			closeShards(shards)
+			return nil, err
+		}
+		shards = append(shards, &liveShard[T]{symbols: symbols, channel: channel, cancel: cancel})
+	}
+
+	return shards, nil
+}
+
+// runShards starts forwarding every shard's connection into the stream's output channel. It is
+// called with mu held.
+func (s *LivePriceStream[T]) runShards(ctx context.Context, shards []*liveShard[T]) {
+	outputChan, delayed := s.outputChan, s.delayed
+	for _, shard := range shards {
+		s.forwarders.Add(1)
+		go func(shard *liveShard[T]) {
+			defer s.forwarders.Done()
+			s.forwardData(ctx, shard.channel, shard.symbols, outputChan, delayed)
+		}(shard)
+	}
+}
+
+func closeShards[T any](shards []*liveShard[T]) {
+	for _, shard := range shards {
+		shard.cancel()
+	}
+}
+
+func shardGroups[T any](shards []*liveShard[T]) [][]string {
+	groups := make([][]string, len(shards))
+	for i, shard := range shards {
+		groups[i] = shard.symbols
+	}
+	return groups
+}
+
+// shardSymbols splits symbols into groups of at most size symbols. An empty symbol list yields
+// a single group, which streams every symbol.
+func shardSymbols(symbols []string, size int) [][]string {
+	if len(symbols) == 0 {
+		return [][]string{nil}
+	}
+
+	var groups [][]string
+	for len(symbols) > size {
+		groups = append(groups, symbols[:size:size])
+		symbols = symbols[size:]
+	}
+	return append(groups, symbols)
+}
+
+// planShards computes the shard layout for symbols starting from the current groups. Existing
+// groups keep their symbols where possible so that only changed shards reconnect; reuse[i] is
+// the index of the current group identical to groups[i], or -1 if groups[i] needs a new
+// connection. When the stable layout would use more connections than needed, the symbols are
+// resharded from scratch.
+func planShards(current [][]string, symbols []string, size int) (groups [][]string, reuse []int) {
+	wanted := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		wanted[symbol] = true
+	}
+
+	assigned := make(map[string]bool, len(symbols))
+	for _, group := range current {
+		kept := slices.DeleteFunc(slices.Clone(group), func(symbol string) bool {
+			return !wanted[symbol]
+		})
+		for _, symbol := range kept {
+			assigned[symbol] = true
+		}
+		if len(kept) > 0 {
+			groups = append(groups, kept)
+		}
+	}
+
+	var added []string
+	for _, symbol := range symbols {
+		if !assigned[symbol] {
+			added = append(added, symbol)
+		}
+	}
+
+	for i := range groups {
+		if room := size - len(groups[i]); room > 0 && len(added) > 0 {
+			n := min(room, len(added))
+			groups[i] = append(groups[i], added[:n]...)
+			added = added[n:]
+		}
+	}
+	if len(added) > 0 {
+		groups = append(groups, shardSymbols(added, size)...)
+	}
+
+	if needed := (len(symbols) + size - 1) / size; len(groups) > needed {
+		groups = shardSymbols(symbols, size)
+	}
+
+	reuse = make([]int, len(groups))
+	used := make([]bool, len(current))
+	for i, group := range groups {
+		reuse[i] = -1
+		for j := range current {
+			if !used[j] && slices.Equal(group, current[j]) {
+				reuse[i] = j
+				used[j] = true
+				break
+			}
+		}
+	}
+
+	return groups, reuse
+}
@@ -0,0 +1,180 @@
+package laplace
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardSymbols(t *testing.T) {
+	require.Equal(t, [][]string{nil}, shardSymbols(nil, 2))
+	require.Equal(t, [][]string{{"A", "B"}, {"C"}}, shardSymbols([]string{"A", "B", "C"}, 2))
+}
+
+func TestPlanShardsKeepsUnchangedGroups(t *testing.T) {
+	current := [][]string{{"A", "B"}, {"C"}}
+
+	groups, reuse := planShards(current, []string{"A", "B", "C", "D"}, 2)
+	require.Equal(t, [][]string{{"A", "B"}, {"C", "D"}}, groups)
+	require.Equal(t, []int{0, -1}, reuse)
+
+	groups, reuse = planShards(current, []string{"A", "B", "C", "D", "E"}, 2)
+	require.Equal(t, [][]string{{"A", "B"}, {"C", "D"}, {"E"}}, groups)
+	require.Equal(t, []int{0, -1, -1}, reuse)
+
+	groups, reuse = planShards(current, []string{"B", "C"}, 2)
+	require.Equal(t, [][]string{{"B", "C"}}, groups)
+	require.Equal(t, []int{-1}, reuse)
+
+	groups, reuse = planShards(current, []string{"C"}, 2)
+	require.Equal(t, [][]string{{"C"}}, groups)
+	require.Equal(t, []int{1}, reuse)
+}
+
+func TestLivePriceStreamSharding(t *testing.T) {
+	var mu sync.Mutex
+	var filters []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter := r.URL.Query().Get("filter")
+		mu.Lock()
+		filters = append(filters, filter)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		for _, symbol := range strings.Split(filter, ",") {
+			fmt.Fprintf(w, "data:{\"s\":\"%s\",\"p\":1}\n\n", symbol)
+		}
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	client := newOfflineTestClient(t, srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	symbols := make([]string, 5)
+	for i := range symbols {
+		symbols[i] = fmt.Sprintf("SYM%d", i)
+	}
+
+	stream := client.GetLivePriceStreamForUS()
+	stream.SetShardSize(2)
+	require.NoError(t, stream.Subscribe(ctx, symbols))
+	defer stream.Close()
+
+	received := map[string]bool{}
+	for len(received) < len(symbols) {
+		data := <-stream.Receive()
+		require.NoError(t, data.Error)
+		received[data.Data.Symbol] = true
+	}
+
+	mu.Lock()
+	require.ElementsMatch(t, []string{"SYM0,SYM1", "SYM2,SYM3", "SYM4"}, filters)
+	filters = nil
+	mu.Unlock()
+
+	require.NoError(t, stream.AddSymbols("SYM5"))
+
+	data := <-stream.Receive()
+	for data.Data.Symbol != "SYM5" {
+		data = <-stream.Receive()
+	}
+
+	mu.Lock()
+	require.Equal(t, []string{"SYM4,SYM5"}, filters)
+	mu.Unlock()
+
+	require.NoError(t, stream.RemoveSymbols("SYM0"))
+	require.Equal(t, []string{"SYM1", "SYM2", "SYM3", "SYM4", "SYM5"}, stream.Symbols())
+	require.Error(t, stream.RemoveSymbols(stream.Symbols()...))
+}
+
+func TestLivePriceStreamShardError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter := r.URL.Query().Get("filter")
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		for _, symbol := range strings.Split(filter, ",") {
+			fmt.Fprintf(w, "data:{\"s\":\"%s\",\"p\":1}\n\n", symbol)
+		}
+		w.(http.Flusher).Flush()
+
+		// The connection of the second shard is closed by the server
+		if filter == "SYM2" {
+			return
+		}
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	client := newOfflineTestClient(t, srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := client.GetLivePriceStreamForUS()
+	stream.SetShardSize(2)
+	require.NoError(t, stream.Subscribe(ctx, []string{"SYM0", "SYM1", "SYM2"}))
+	defer stream.Close()
+
+	var symbols []string
+	err := stream.Run(ctx, func(data USStockLiveData, err error) error {
+		symbols = append(symbols, data.Symbol)
+		return nil
+	})
+
+	var shardErr *LiveShardError
+	require.ErrorAs(t, err, &shardErr)
+	require.Equal(t, []string{"SYM2"}, shardErr.Symbols)
+	require.ErrorIs(t, err, ErrStreamEnded)
+	require.ErrorContains(t, err, "live price connection for SYM2 failed")
+	require.Contains(t, symbols, "SYM2")
+	require.Equal(t, []string{"SYM0", "SYM1", "SYM2"}, stream.Symbols())
+}
+
+func TestLivePriceStreamAllSymbols(t *testing.T) {
+	var mu sync.Mutex
+	var filters []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		filters = append(filters, r.URL.Query().Get("filter"))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	client := newOfflineTestClient(t, srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := client.GetLivePriceStreamForUS()
+	require.NoError(t, stream.Subscribe(ctx, nil))
+	defer stream.Close()
+
+	// A stream of every symbol is not narrowed to the added ones
+	require.NoError(t, stream.AddSymbols("AAPL"))
+	require.Empty(t, stream.Symbols())
+	require.ErrorContains(t, stream.RemoveSymbols("AAPL"), "stream of every symbol")
+
+	mu.Lock()
+	require.Equal(t, []string{""}, filters)
+	mu.Unlock()
+}
@@ -2,9 +2,16 @@ package laplace
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"slices"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetLivePriceForBIST(t *testing.T) {
@@ -480,3 +487,80 @@ func TestLiveBidAskDataStructure(t *testing.T) {
 		t.Errorf("Expected type pr, got %s", response.Type)
 	}
 }
+
+func TestLivePriceFallbackToDelayed(t *testing.T) {
+	var liveAllowed atomic.Bool
+
+	serveSSE := func(w http.ResponseWriter, r *http.Request, price float64) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		for {
+			fmt.Fprintf(w, "data:{\"symbol\":\"AKBNK\",\"type\":\"pr\",\"data\":{\"s\":\"AKBNK\",\"p\":%v}}\n\n", price)
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/stock/price/live", func(w http.ResponseWriter, r *http.Request) {
+		if !liveAllowed.Load() {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message":"you don't have access to this endpoint"}`)
+			return
+		}
+		serveSSE(w, r, 2)
+	})
+	mux.HandleFunc("/api/v1/stock/price/delayed", func(w http.ResponseWriter, r *http.Request) {
+		serveSSE(w, r, 1)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := newOfflineTestClient(t, srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := client.GetLivePriceStreamForBIST()
+	stream.SetDelayedFallback(true, 50*time.Millisecond)
+	require.NoError(t, stream.Subscribe(ctx, []string{"AKBNK"}))
+	defer stream.Close()
+
+	data := <-stream.Receive()
+	require.NoError(t, data.Error)
+	require.True(t, data.Delayed)
+	require.Equal(t, 1.0, data.Data.Data.ClosePrice)
+	require.True(t, stream.IsDelayed())
+
+	liveAllowed.Store(true)
+
+	for data := range stream.Receive() {
+		require.NoError(t, data.Error)
+		if !data.Delayed {
+			require.Equal(t, 2.0, data.Data.Data.ClosePrice)
+			break
+		}
+	}
+	require.False(t, stream.IsDelayed())
+}
+
+func TestLivePriceFallbackDisabled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message":"forbidden","error_code":"no_access_to_level"}`)
+	}))
+	defer srv.Close()
+
+	client := newOfflineTestClient(t, srv.URL)
+
+	stream := client.GetLivePriceStreamForBIST()
+	stream.SetDelayedFallback(false, 0)
+
+	err := stream.Subscribe(context.Background(), []string{"AKBNK"})
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrNoAccessToLevel))
+}
@@ -0,0 +1,476 @@
+package laplace
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LiveWatchdogEventType represents the kind of event emitted by a LiveWatchdog
+type LiveWatchdogEventType string
+
+const (
+	LiveWatchdogEventStale     LiveWatchdogEventType = "stale"
+	LiveWatchdogEventRecovered LiveWatchdogEventType = "recovered"
+)
+
+// LiveWatchdogEvent reports a symbol or the whole feed going stale or recovering. Symbol is
+// empty for feed-level events.
+type LiveWatchdogEvent struct {
+	Type         LiveWatchdogEventType
+	Symbol       string
+	MarketState  string
+	LastUpdate   time.Time
+	LastReceived time.Time
+	Age          time.Duration
+	Threshold    time.Duration
+}
+
+// LiveWatchdogThresholds holds the maximum time without updates before a symbol or the feed is
+// reported stale. A zero value disables the corresponding check.
+type LiveWatchdogThresholds struct {
+	Symbol time.Duration
+	Feed   time.Duration
+}
+
+// LiveWatchdogOptions configures a LiveWatchdog.
+type LiveWatchdogOptions struct {
+	// Thresholds is used when no market state is known or StateThresholds has no entry for it.
+	Thresholds LiveWatchdogThresholds
+	// StateThresholds overrides Thresholds per market state, e.g. zero thresholds for a closed
+	// market so it does not raise alerts.
+	StateThresholds map[string]LiveWatchdogThresholds
+	// SymbolStates, when set, returns the market state of every symbol in a single lookup,
+	// which scales to large symbol sets. It takes precedence over SymbolState.
+	SymbolStates func(ctx context.Context) (map[string]string, error)
+	// SymbolState, when set, returns the market state of a symbol. Symbols whose state is due
+	// for a refresh are looked up concurrently, at most StateConcurrency at a time.
+	SymbolState func(ctx context.Context, symbol string) (string, error)
+	// StateConcurrency bounds the concurrent SymbolState lookups. Defaults to 8.
+	StateConcurrency int
+	// FeedState, when set, returns the market state used for feed-level checks.
+	FeedState func(ctx context.Context) (string, error)
+	// StateRefresh is how long a looked up market state is cached. Defaults to one minute.
+	StateRefresh time.Duration
+	// CheckInterval is how often staleness is evaluated. Defaults to one second.
+	CheckInterval time.Duration
+}
+
+// StockStateLookup adapts GetStateForStock for use as LiveWatchdogOptions.SymbolState.
+func (c *Client) StockStateLookup() func(ctx context.Context, symbol string) (string, error) {
+	return func(ctx context.Context, symbol string) (string, error) {
+		state, err := c.GetStateForStock(ctx, symbol)
+		if err != nil {
+			return "", err
+		}
+		return state.State, nil
+	}
+}
+
+// StockStatesLookup adapts GetStateOfAllStocks for the given region for use as
+// LiveWatchdogOptions.SymbolStates.
+func (c *Client) StockStatesLookup(region Region) func(ctx context.Context) (map[string]string, error) {
+	return func(ctx context.Context) (map[string]string, error) {
+		states := make(map[string]string)
+		// The state endpoints number their pages from 0
+		for page := 0; page < stockStatesMaxPages; page++ {
+			resp, err := c.GetStateOfAllStocks(ctx, region, page, stockStatesPageSize)
+			if err != nil {
+				return nil, err
+			}
+			for _, state := range resp.Items {
+				if state != nil && state.StockSymbol != nil {
+					states[*state.StockSymbol] = state.State
+				}
+			}
+			if len(resp.Items) < stockStatesPageSize || len(states) >= resp.RecordCount {
+				break
+			}
+		}
+		return states, nil
+	}
+}
+
+// stockStatesPageSize and stockStatesMaxPages bound a StockStatesLookup
+const (
+	stockStatesPageSize = 1000
+	stockStatesMaxPages = 20
+)
+
+// MarketStateLookup adapts GetStateForMarket for the given market for use as
+// LiveWatchdogOptions.FeedState.
+func (c *Client) MarketStateLookup(market string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		state, err := c.GetStateForMarket(ctx, market)
+		if err != nil {
+			return "", err
+		}
+		return state.State, nil
+	}
+}
+
+type watchedSymbol struct {
+	lastDate     int64
+	lastUpdate   time.Time
+	lastReceived time.Time
+	stale        bool
+}
+
+type cachedState struct {
+	state     string
+	fetchedAt time.Time
+}
+
+// LiveWatchdog tracks per-symbol updates and feed-level heartbeats of a LivePriceStream and
+// reports symbols that silently stop ticking while the connection stays up.
+type LiveWatchdog struct {
+	mu           sync.Mutex
+	opts         LiveWatchdogOptions
+	c            *Client
+	symbols      func() []string
+	watched      map[string]*watchedSymbol
+	states       map[string]cachedState
+	startedAt    time.Time
+	lastReceived time.Time
+	feedStale    bool
+	events       chan LiveWatchdogEvent
+	cancel       context.CancelFunc
+	done         chan struct{}
+	stopped      bool
+	// unobserve stops the stream from notifying the watchdog
+	unobserve func()
+	stopOnce  sync.Once
+}
+
+// NewWatchdog creates a watchdog observing the stream. Call Start to begin evaluating staleness.
+func (s *LivePriceStream[T]) NewWatchdog(opts LiveWatchdogOptions) *LiveWatchdog {
+	if opts.StateRefresh <= 0 {
+		opts.StateRefresh = time.Minute
+	}
+	if opts.CheckInterval <= 0 {
+		opts.CheckInterval = time.Second
+	}
+	if opts.StateConcurrency <= 0 {
+		opts.StateConcurrency = 8
+	}
+
+	w := &LiveWatchdog{
+		opts:    opts,
+		c:       s.c,
+		symbols: s.Symbols,
+		watched: make(map[string]*watchedSymbol),
+		states:  make(map[string]cachedState),
+		events:  make(chan LiveWatchdogEvent, 64),
+	}
+
+	w.unobserve = s.addObserver(func(result LivePriceResult[T], receivedAt time.Time) {
+		var tick liveTick
+		if result.Error == nil {
+			tick, _ = any(result.Data).(liveTick)
+		}
+		w.observe(tick, receivedAt)
+	})
+
+	return w
+}
+
+// Events returns the channel stale and recovered events are delivered on. It is closed once
+// the watchdog stops.
+func (w *LiveWatchdog) Events() <-chan LiveWatchdogEvent {
+	return w.events
+}
+
+// Start begins evaluating staleness until ctx is done or Stop is called. A stopped watchdog
+// cannot be started again.
+func (w *LiveWatchdog) Start(ctx context.Context) {
+	w.mu.Lock()
+	if w.cancel != nil || w.stopped {
+		w.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	w.cancel = cancel
+	w.done = make(chan struct{})
+	w.startedAt = time.Now()
+	w.mu.Unlock()
+
+	go w.run(ctx)
+}
+
+// Stop stops the watchdog, detaches it from the stream and closes the events channel.
+func (w *LiveWatchdog) Stop() {
+	w.stopOnce.Do(w.unobserve)
+
+	w.mu.Lock()
+	cancel, done := w.cancel, w.done
+	if cancel == nil && !w.stopped {
+		// No check loop was started to close the events channel
+		close(w.events)
+	}
+	w.stopped = true
+	w.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+func (w *LiveWatchdog) run(ctx context.Context) {
+	defer close(w.done)
+	defer close(w.events)
+
+	ticker := time.NewTicker(w.opts.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			w.check(ctx, now)
+		}
+	}
+}
+
+// observe records a received message. Any message counts as a feed heartbeat; a symbol only
+// counts as updated when its Date advances, so repeated stale snapshots are not mistaken for ticks.
+func (w *LiveWatchdog) observe(tick liveTick, receivedAt time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.lastReceived = receivedAt
+
+	if tick == nil || tick.tickSymbol() == "" {
+		return
+	}
+
+	symbol := tick.tickSymbol()
+	ws, ok := w.watched[symbol]
+	if !ok {
+		ws = &watchedSymbol{}
+		w.watched[symbol] = ws
+	}
+
+	ws.lastReceived = receivedAt
+	date := tick.tickDate()
+	if date == 0 || date > ws.lastDate {
+		ws.lastDate = date
+		ws.lastUpdate = receivedAt
+	}
+}
+
+func (w *LiveWatchdog) check(ctx context.Context, now time.Time) {
+	// The stream's lock is never taken while holding the watchdog's
+	subscribed := w.symbols()
+
+	w.mu.Lock()
+	if len(subscribed) > 0 {
+		keep := make(map[string]bool, len(subscribed))
+		for _, symbol := range subscribed {
+			keep[symbol] = true
+			if _, ok := w.watched[symbol]; !ok {
+				w.watched[symbol] = &watchedSymbol{lastUpdate: now}
+			}
+		}
+		for symbol := range w.watched {
+			if !keep[symbol] {
+				delete(w.watched, symbol)
+			}
+		}
+	}
+
+	symbols := make([]string, 0, len(w.watched))
+	for symbol := range w.watched {
+		symbols = append(symbols, symbol)
+	}
+	w.mu.Unlock()
+
+	feedState := w.feedState(ctx, now)
+	w.evaluateFeed(now, feedState)
+
+	states := w.symbolStates(ctx, symbols, now)
+	for _, symbol := range symbols {
+		w.evaluateSymbol(now, symbol, states[symbol])
+	}
+}
+
+func (w *LiveWatchdog) evaluateFeed(now time.Time, state string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	lastReceived := w.lastReceived
+	if lastReceived.IsZero() {
+		lastReceived = w.startedAt
+	}
+
+	threshold := w.thresholds(state).Feed
+	age := now.Sub(lastReceived)
+	stale := threshold > 0 && age > threshold
+
+	if stale == w.feedStale {
+		return
+	}
+	w.feedStale = stale
+
+	w.emit(LiveWatchdogEvent{
+		Type:         eventTypeFor(stale),
+		MarketState:  state,
+		LastUpdate:   lastReceived,
+		LastReceived: w.lastReceived,
+		Age:          age,
+		Threshold:    threshold,
+	})
+}
+
+func (w *LiveWatchdog) evaluateSymbol(now time.Time, symbol, state string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ws, ok := w.watched[symbol]
+	if !ok {
+		return
+	}
+
+	threshold := w.thresholds(state).Symbol
+	age := now.Sub(ws.lastUpdate)
+	stale := threshold > 0 && age > threshold
+
+	if stale == ws.stale {
+		return
+	}
+	ws.stale = stale
+
+	lastUpdate := liveDateToTime(ws.lastDate)
+	if lastUpdate.IsZero() {
+		lastUpdate = ws.lastUpdate
+	}
+
+	w.emit(LiveWatchdogEvent{
+		Type:         eventTypeFor(stale),
+		Symbol:       symbol,
+		MarketState:  state,
+		LastUpdate:   lastUpdate,
+		LastReceived: ws.lastReceived,
+		Age:          age,
+		Threshold:    threshold,
+	})
+}
+
+func eventTypeFor(stale bool) LiveWatchdogEventType {
+	if stale {
+		return LiveWatchdogEventStale
+	}
+	return LiveWatchdogEventRecovered
+}
+
+// emit delivers an event without blocking the check loop; events are dropped when the consumer
+// falls behind.
+func (w *LiveWatchdog) emit(event LiveWatchdogEvent) {
+	select {
+	case w.events <- event:
+	default:
+		w.c.logger.Warnf("live watchdog: dropping %s event for %q, consumer is not keeping up", event.Type, event.Symbol)
+	}
+}
+
+func (w *LiveWatchdog) thresholds(state string) LiveWatchdogThresholds {
+	if t, ok := w.opts.StateThresholds[state]; ok && state != "" {
+		return t
+	}
+	return w.opts.Thresholds
+}
+
+// symbolStates returns the market state of every symbol, looking up those not cached within
+// StateRefresh: all at once through SymbolStates, or concurrently through SymbolState.
+func (w *LiveWatchdog) symbolStates(ctx context.Context, symbols []string, now time.Time) map[string]string {
+	states := make(map[string]string, len(symbols))
+	if w.opts.SymbolStates == nil && w.opts.SymbolState == nil {
+		return states
+	}
+
+	var due []string
+	w.mu.Lock()
+	for _, symbol := range symbols {
+		cached, ok := w.states[symbol]
+		states[symbol] = cached.state
+		if !ok || now.Sub(cached.fetchedAt) >= w.opts.StateRefresh {
+			due = append(due, symbol)
+		}
+	}
+	w.mu.Unlock()
+
+	if len(due) == 0 {
+		return states
+	}
+
+	if w.opts.SymbolStates != nil {
+		fetched, err := w.opts.SymbolStates(ctx)
+		if err != nil {
+			w.c.logger.Warnf("live watchdog: failed to look up market states: %v", err)
+		}
+
+		w.mu.Lock()
+		for _, symbol := range due {
+			if state, ok := fetched[symbol]; ok {
+				states[symbol] = state
+			}
+			w.states[symbol] = cachedState{state: states[symbol], fetchedAt: now}
+		}
+		w.mu.Unlock()
+		return states
+	}
+
+	var wg sync.WaitGroup
+	results := make([]string, len(due))
+	sem := make(chan struct{}, w.opts.StateConcurrency)
+	for i, symbol := range due {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i] = w.cachedLookup(ctx, symbol, now, func(ctx context.Context) (string, error) {
+				return w.opts.SymbolState(ctx, symbol)
+			})
+		}()
+	}
+	wg.Wait()
+
+	for i, symbol := range due {
+		states[symbol] = results[i]
+	}
+	return states
+}
+
+func (w *LiveWatchdog) feedState(ctx context.Context, now time.Time) string {
+	if w.opts.FeedState == nil {
+		return ""
+	}
+	// Symbols are never empty, so the empty key is free for the feed state.
+	return w.cachedLookup(ctx, "", now, w.opts.FeedState)
+}
+
+func (w *LiveWatchdog) cachedLookup(ctx context.Context, key string, now time.Time, lookup func(ctx context.Context) (string, error)) string {
+	w.mu.Lock()
+	cached, ok := w.states[key]
+	w.mu.Unlock()
+
+	if ok && now.Sub(cached.fetchedAt) < w.opts.StateRefresh {
+		return cached.state
+	}
+
+	state, err := lookup(ctx)
+	if err != nil {
+		w.c.logger.Warnf("live watchdog: failed to look up market state for %q: %v", key, err)
+		state = cached.state
+	}
+
+	w.mu.Lock()
+	w.states[key] = cachedState{state: state, fetchedAt: now}
+	w.mu.Unlock()
+
+	return state
+}
@@ -0,0 +1,251 @@
+package laplace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newSSETestServer serves every line written to the returned channel as an SSE data line.
+func newSSETestServer(t *testing.T) (*httptest.Server, chan<- string) {
+	t.Helper()
+
+	lines := make(chan string, 16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		for {
+			select {
+			case line := <-lines:
+				fmt.Fprintf(w, "data:%s\n\n", line)
+				w.(http.Flusher).Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, lines
+}
+
+func newOfflineTestClient(t *testing.T, baseURL string) *Client {
+	t.Helper()
+
+	client, err := NewClient(LaplaceConfiguration{APIKey: "test", BaseURL: baseURL})
+	require.NoError(t, err)
+	return client
+}
+
+func TestLiveWatchdogStaleAndRecovered(t *testing.T) {
+	srv, lines := newSSETestServer(t)
+	client := newOfflineTestClient(t, srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := client.GetLivePriceStreamForBIST()
+	watchdog := stream.NewWatchdog(LiveWatchdogOptions{
+		Thresholds:    LiveWatchdogThresholds{Symbol: 150 * time.Millisecond},
+		CheckInterval: 20 * time.Millisecond,
+	})
+
+	require.NoError(t, stream.Subscribe(ctx, []string{"AKBNK"}))
+	defer stream.Close()
+
+	go func() {
+		for range stream.Receive() {
+		}
+	}()
+
+	watchdog.Start(ctx)
+	defer watchdog.Stop()
+
+	tick := func(date int64) {
+		lines <- fmt.Sprintf(`{"symbol":"AKBNK","type":"pr","data":{"s":"AKBNK","p":10,"d":%d}}`, date)
+	}
+
+	tick(time.Now().UnixMilli())
+
+	event := <-watchdog.Events()
+	require.Equal(t, LiveWatchdogEventStale, event.Type)
+	require.Equal(t, "AKBNK", event.Symbol)
+	require.GreaterOrEqual(t, event.Age, 150*time.Millisecond)
+
+	tick(time.Now().UnixMilli())
+
+	event = <-watchdog.Events()
+	require.Equal(t, LiveWatchdogEventRecovered, event.Type)
+	require.Equal(t, "AKBNK", event.Symbol)
+}
+
+func TestLiveWatchdogRepeatedDateIsNotAnUpdate(t *testing.T) {
+	client := newOfflineTestClient(t, "http://localhost")
+
+	stream := client.GetLivePriceStreamForBIST()
+	watchdog := stream.NewWatchdog(LiveWatchdogOptions{
+		Thresholds: LiveWatchdogThresholds{Symbol: time.Minute},
+	})
+
+	start := time.Now()
+	msg := LiveMessageV2[BISTStockLiveData]{Symbol: "AKBNK", Data: BISTStockLiveData{Symbol: "AKBNK", Date: 1000}}
+	watchdog.observe(msg, start)
+	watchdog.observe(msg, start.Add(2*time.Minute))
+
+	watchdog.evaluateSymbol(start.Add(2*time.Minute), "AKBNK", "")
+	event := <-watchdog.events
+	require.Equal(t, LiveWatchdogEventStale, event.Type)
+	require.Equal(t, start.Add(2*time.Minute), event.LastReceived)
+}
+
+func TestLiveWatchdogClosedMarketDoesNotAlert(t *testing.T) {
+	client := newOfflineTestClient(t, "http://localhost")
+
+	stream := client.GetLivePriceStreamForBIST()
+	watchdog := stream.NewWatchdog(LiveWatchdogOptions{
+		Thresholds: LiveWatchdogThresholds{Symbol: time.Second, Feed: time.Second},
+		StateThresholds: map[string]LiveWatchdogThresholds{
+			"closed": {},
+		},
+		SymbolState: func(ctx context.Context, symbol string) (string, error) {
+			return "closed", nil
+		},
+		FeedState: func(ctx context.Context) (string, error) {
+			return "closed", nil
+		},
+	})
+
+	start := time.Now()
+	watchdog.startedAt = start
+	watchdog.observe(BISTStockLiveData{Symbol: "AKBNK", Date: 1000}, start)
+
+	watchdog.check(context.Background(), start.Add(time.Hour))
+	require.Empty(t, watchdog.events)
+}
+
+func TestLiveWatchdogStopDetachesFromStream(t *testing.T) {
+	client := newOfflineTestClient(t, "http://localhost")
+
+	stream := client.GetLivePriceStreamForBIST()
+	observers := len(stream.observers)
+
+	watchdog := stream.NewWatchdog(LiveWatchdogOptions{})
+	require.Len(t, stream.observers, observers+1)
+
+	watchdog.Start(context.Background())
+	watchdog.Stop()
+	watchdog.Stop()
+	require.Len(t, stream.observers, observers)
+
+	// A watchdog that was never started is detached as well, and its events channel closed
+	unstarted := stream.NewWatchdog(LiveWatchdogOptions{})
+	unstarted.Stop()
+	unstarted.Stop()
+	require.Len(t, stream.observers, observers)
+	_, ok := <-unstarted.Events()
+	require.False(t, ok)
+
+	unstarted.Start(context.Background())
+	require.Nil(t, unstarted.cancel)
+}
+
+func TestLiveWatchdogBatchStateLookup(t *testing.T) {
+	client := newOfflineTestClient(t, "http://localhost")
+
+	var lookups atomic.Int32
+	stream := client.GetLivePriceStreamForBIST()
+	watchdog := stream.NewWatchdog(LiveWatchdogOptions{
+		Thresholds:      LiveWatchdogThresholds{Symbol: time.Second},
+		StateThresholds: map[string]LiveWatchdogThresholds{"closed": {}},
+		SymbolStates: func(ctx context.Context) (map[string]string, error) {
+			lookups.Add(1)
+			return map[string]string{"AKBNK": "closed", "GARAN": "open"}, nil
+		},
+		SymbolState: func(ctx context.Context, symbol string) (string, error) {
+			t.Errorf("unexpected lookup of %s", symbol)
+			return "", nil
+		},
+	})
+
+	start := time.Now()
+	watchdog.startedAt = start
+	for _, symbol := range []string{"AKBNK", "GARAN", "THYAO"} {
+		watchdog.observe(BISTStockLiveData{Symbol: symbol, Date: 1000}, start)
+	}
+
+	watchdog.check(context.Background(), start.Add(time.Hour))
+	watchdog.check(context.Background(), start.Add(time.Hour+time.Second))
+	require.Equal(t, int32(1), lookups.Load())
+
+	// The closed market raises no alert; GARAN and THYAO, without a known state, do
+	var stale []string
+	for len(watchdog.events) > 0 {
+		stale = append(stale, (<-watchdog.events).Symbol)
+	}
+	require.ElementsMatch(t, []string{"GARAN", "THYAO"}, stale)
+}
+
+func TestLiveWatchdogConcurrentStateLookups(t *testing.T) {
+	client := newOfflineTestClient(t, "http://localhost")
+
+	var running, peak atomic.Int32
+	stream := client.GetLivePriceStreamForBIST()
+	watchdog := stream.NewWatchdog(LiveWatchdogOptions{
+		StateConcurrency: 4,
+		SymbolState: func(ctx context.Context, symbol string) (string, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return "open", nil
+		},
+	})
+
+	start := time.Now()
+	for i := 0; i < 16; i++ {
+		watchdog.observe(BISTStockLiveData{Symbol: fmt.Sprintf("SYM%d", i), Date: 1000}, start)
+	}
+
+	began := time.Now()
+	watchdog.check(context.Background(), start)
+	require.Less(t, time.Since(began), 16*20*time.Millisecond)
+	require.LessOrEqual(t, peak.Load(), int32(4))
+	require.Greater(t, peak.Load(), int32(1))
+	require.Len(t, watchdog.states, 16)
+}
+
+func TestStockStatesLookup(t *testing.T) {
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/state/stock/all", r.URL.Path)
+		require.Equal(t, "tr", r.URL.Query().Get("region"))
+		pages = append(pages, r.URL.Query().Get("page"))
+
+		akbnk, garan := "AKBNK", "GARAN"
+		json.NewEncoder(w).Encode(PaginatedResponse[*MarketState]{RecordCount: 2, Items: []*MarketState{
+			{StockSymbol: &akbnk, State: "open"},
+			{StockSymbol: &garan, State: "closed"},
+			{State: "market without a stock"},
+		}})
+	}))
+	defer srv.Close()
+
+	states, err := newOfflineTestClient(t, srv.URL).StockStatesLookup(RegionTr)(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"AKBNK": "open", "GARAN": "closed"}, states)
+	require.Equal(t, []string{"0"}, pages)
+}
@@ -1,13 +1,12 @@
 package laplace
 
 import (
-	"bufio"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -384,6 +383,9 @@ func (c *Client) GetNewsV2(ctx context.Context, params GetNewsParams) (*Paginate
 type NewsStreamResult struct {
 	Data  []NewsV2
 	Error error
+	// Prices holds the latest price of the tickers mentioned in Data when the stream has a
+	// price source, keyed by symbol
+	Prices map[string]float64
 }
 
 func sendNewsSSERequest(
@@ -392,30 +394,11 @@ func sendNewsSSERequest(
 	url string,
 ) (<-chan NewsStreamResult, func(), error) {
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	resp, err := openSSE(ctx, c, url)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Set headers
-	req.Header.Set("Accept", "text/event-stream")
-	req.Header.Set("Cache-Control", "no-cache")
-	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	// Send the request
-	resp, err := c.cli.Do(req.WithContext(ctx))
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// Check the response status
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		defer resp.Body.Close()
-		return nil, nil, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(bodyBytes))
-	}
-
 	// Create a single channel for results
 	results := make(chan NewsStreamResult)
 
@@ -427,27 +410,22 @@ func sendNewsSSERequest(
 		defer close(results)
 		defer cancel()
 
-		scanner := bufio.NewScanner(resp.Body)
-		for scanner.Scan() {
+		send := func(result NewsStreamResult) bool {
 			select {
+			case results <- result:
+				return true
 			case <-ctxWithCancel.Done():
-				return
-			default:
-				line := scanner.Text()
-				if strings.HasPrefix(line, "data:") {
-					data := strings.TrimPrefix(line, "data:")
-					var event []NewsV2
-					if err := json.Unmarshal([]byte(data), &event); err != nil {
-						results <- NewsStreamResult{Error: fmt.Errorf("error unmarshalling event: %w", err)}
-						continue
-					}
-					results <- NewsStreamResult{Data: event}
-				}
+				return false
 			}
 		}
 
-		if err := scanner.Err(); err != nil {
-			results <- NewsStreamResult{Error: fmt.Errorf("error reading SSE stream: %w", err)}
+		err := decodeSSE(resp.Body, c.codec, nil, func(result LivePriceResult[[]NewsV2]) bool {
+			return send(NewsStreamResult{Data: result.Data, Error: result.Error})
+		})
+
+		// A cancelled connection was closed on purpose, so its end is not reported
+		if ctxWithCancel.Err() == nil {
+			send(NewsStreamResult{Error: streamEndError(err)})
 		}
 	}()
 
@@ -466,17 +444,50 @@ type StreamNewsParams struct {
 	ApiSource   []string
 }
 
-// NewsStream handles live news streaming for a specific locale and filters
+// DefaultNewsReconnectMaxBackoff is the longest a news stream waits between reconnect attempts.
+const DefaultNewsReconnectMaxBackoff = 30 * time.Second
+
+const (
+	// newsDedupCapacity is the number of recent news IDs a stream remembers to drop duplicates
+	newsDedupCapacity = 1000
+	// newsBackfillPageSize and newsBackfillMaxPages bound the backfill after a reconnect
+	newsBackfillPageSize = 100
+	newsBackfillMaxPages = 10
+)
+
+// NewsStream handles live news streaming for a specific locale and filters. Delivered news
+// items are deduplicated by ID, and after a reconnect the gap is backfilled through GetNewsV2
+// so that every item is delivered once, in order.
 type NewsStream struct {
 	mu           sync.RWMutex
 	ctx          context.Context
 	cancel       context.CancelFunc
-	sseChan      <-chan NewsStreamResult
 	outputChan   chan NewsStreamResult
 	c            *Client
 	params       StreamNewsParams
 	closed       bool
 	isSubscribed bool
+
+	reconnect    bool
+	reconnectMin time.Duration
+	reconnectMax time.Duration
+	dedup        *newsDedup
+	predicates   []NewsPredicate
+	prices       NewsPriceSource
+}
+
+// SetReconnect configures whether the stream reconnects when its connection is lost, waiting
+// between attempts with exponential backoff up to maxBackoff. Reconnecting is enabled by
+// default; when disabled, a lost connection is reported as a terminal StreamTransportError.
+func (s *NewsStream) SetReconnect(enabled bool, maxBackoff time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reconnect = enabled
+	if maxBackoff > 0 {
+		s.reconnectMax = maxBackoff
+		s.reconnectMin = min(s.reconnectMin, maxBackoff)
+	}
 }
 
 // Subscribe starts receiving news from the stream
@@ -488,6 +499,8 @@ func (s *NewsStream) Subscribe(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	resubscribe := s.isSubscribed
+
 	// Cleanup existing stream
 	if err := s.cleanupExistingStream(); err != nil {
 		return fmt.Errorf("failed to cleanup existing stream: %w", err)
@@ -502,10 +515,47 @@ func (s *NewsStream) Subscribe(ctx context.Context) error {
 		return fmt.Errorf("failed to start streaming: %w", err)
 	}
 
+	if resubscribe && s.c.metrics != nil {
+		s.c.metrics.IncReconnects(newsStreamName(s.params.Region))
+	}
+
 	s.isSubscribed = true
 	return nil
 }
 
+// observeMetrics reports event, latency and decode error telemetry for a received result
+func (s *NewsStream) observeMetrics(result NewsStreamResult, receivedAt time.Time) {
+	if s.c.metrics == nil {
+		return
+	}
+
+	name := newsStreamName(s.params.Region)
+	if result.Error != nil {
+		if IsRecoverable(result.Error) {
+			s.c.metrics.IncDecodeErrors(name)
+		}
+		return
+	}
+
+	for _, news := range result.Data {
+		s.c.metrics.IncEvents(name)
+		if !news.Timestamp.IsZero() {
+			s.c.metrics.ObserveLatency(name, receivedAt.Sub(news.Timestamp))
+		}
+	}
+}
+
+// recordDropped reports the news items of a result that was received but not delivered
+func (s *NewsStream) recordDropped(result NewsStreamResult) {
+	if s.c.metrics == nil {
+		return
+	}
+
+	for i := 0; i < max(len(result.Data), 1); i++ {
+		s.c.metrics.IncDropped(newsStreamName(s.params.Region))
+	}
+}
+
 // Receive returns a channel to receive news data
 func (s *NewsStream) Receive() <-chan NewsStreamResult {
 	s.mu.RLock()
@@ -552,9 +602,26 @@ func (s *NewsStream) cleanupExistingStream() error {
 
 // startStreaming starts the SSE streaming connection
 func (s *NewsStream) startStreaming() error {
+	ctxWithCancel, cancel := context.WithCancel(s.ctx)
+	s.cancel = cancel
+
+	channel, err := s.connect(ctxWithCancel)
+	if err != nil {
+		return fmt.Errorf("failed to establish SSE connection: %w", err)
+	}
+
+	// A stream that delivered news before was resubscribed, which leaves a gap to backfill
+	backfill := !s.dedup.latestTimestamp().IsZero()
+	go s.forwardData(ctxWithCancel, channel, backfill)
+
+	return nil
+}
+
+// connect opens an SSE connection with the stream's filters
+func (s *NewsStream) connect(ctx context.Context) (<-chan NewsStreamResult, error) {
 	reqURL, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/news/stream", s.c.baseUrl), nil)
 	if err != nil {
-		return fmt.Errorf("failed to create SSE request URL: %w", err)
+		return nil, fmt.Errorf("failed to create SSE request URL: %w", err)
 	}
 
 	q := reqURL.URL.Query()
@@ -580,62 +647,275 @@ func (s *NewsStream) startStreaming() error {
 	}
 	reqURL.URL.RawQuery = q.Encode()
 
-	ctxWithCancel, cancel := context.WithCancel(s.ctx)
-	s.cancel = cancel
-
-	channel, _, err := sendNewsSSERequest(ctxWithCancel, s.c, reqURL.URL.String())
-	if err != nil {
-		return fmt.Errorf("failed to establish SSE connection: %w", err)
-	}
-
-	s.sseChan = channel
-	go s.forwardData()
-
-	return nil
+	channel, _, err := sendNewsSSERequest(ctx, s.c, reqURL.URL.String())
+	return channel, err
 }
 
-// forwardData forwards data from SSE channel to output channel
-func (s *NewsStream) forwardData() {
+// forwardData forwards data from the SSE connection to the output channel, reconnecting and
+// backfilling the gap whenever the connection is lost
+func (s *NewsStream) forwardData(ctx context.Context, channel <-chan NewsStreamResult, backfill bool) {
 	defer func() {
 		if r := recover(); r != nil {
 			s.c.logger.Error("panic in news stream forwardData", r)
 		}
 	}()
 
+	for {
+		// Live news arriving while the backfill runs waits on the unread connection, so it is
+		// delivered after the older backfilled news
+		if backfill && !s.backfill(ctx) {
+			return
+		}
+
+		err := s.forwardConnection(ctx, channel)
+		if err == nil {
+			return
+		}
+
+		s.mu.RLock()
+		reconnect := s.reconnect
+		s.mu.RUnlock()
+
+		if !reconnect {
+			s.deliver(ctx, NewsStreamResult{Error: err})
+			return
+		}
+
+		s.c.logger.Warnf("news stream connection lost, reconnecting: %v", err)
+		channel, err = s.reconnectWithBackoff(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.deliver(ctx, NewsStreamResult{Error: err})
+			}
+			return
+		}
+
+		if s.c.metrics != nil {
+			s.c.metrics.IncReconnects(newsStreamName(s.params.Region))
+		}
+		backfill = true
+	}
+}
+
+// forwardConnection delivers the results of a single connection, dropping news items that were
+// delivered before or fail the stream's predicates. It returns the terminal error that ended
+// the connection, or nil once the stream is cancelled or closed.
+func (s *NewsStream) forwardConnection(ctx context.Context, channel <-chan NewsStreamResult) error {
 	for {
 		select {
-		case data, ok := <-s.sseChan:
+		case data, ok := <-channel:
 			if !ok {
-				return
+				return nil
 			}
 
-			s.mu.RLock()
-			outputChan := s.outputChan
-			closed := s.closed
-			s.mu.RUnlock()
+			if data.Error != nil && !IsRecoverable(data.Error) {
+				return data.Error
+			}
 
-			if closed || outputChan == nil {
-				return
+			if data.Error == nil {
+				var ok bool
+				if data, ok = s.admit(data.Data); !ok {
+					continue
+				}
 			}
 
-			select {
-			case outputChan <- data:
-			case <-s.ctx.Done():
-				return
+			s.observeMetrics(data, time.Now())
+			if !s.deliver(ctx, data) {
+				return nil
 			}
-		case <-s.ctx.Done():
-			return
+		case <-ctx.Done():
+			return nil
 		}
 	}
 }
 
+// deliver sends data to the output channel, and reports whether the stream is still open
+func (s *NewsStream) deliver(ctx context.Context, data NewsStreamResult) bool {
+	s.mu.RLock()
+	outputChan := s.outputChan
+	closed := s.closed
+	s.mu.RUnlock()
+
+	if closed || outputChan == nil {
+		s.recordDropped(data)
+		return false
+	}
+
+	select {
+	case outputChan <- data:
+		return true
+	case <-ctx.Done():
+		s.recordDropped(data)
+		return false
+	}
+}
+
+// reconnectWithBackoff reopens the stream's connection, retrying with exponential backoff until
+// it succeeds, ctx ends or the server rejects the connection
+func (s *NewsStream) reconnectWithBackoff(ctx context.Context) (<-chan NewsStreamResult, error) {
+	s.mu.RLock()
+	backoff, maxBackoff := s.reconnectMin, s.reconnectMax
+	s.mu.RUnlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		channel, err := s.connect(ctx)
+		if err == nil {
+			return channel, nil
+		}
+		if !isRetryableStreamError(err) {
+			return nil, fmt.Errorf("failed to reconnect news stream: %w", err)
+		}
+
+		s.c.logger.Warnf("failed to reconnect news stream, retrying in %s: %v", backoff, err)
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// isRetryableStreamError reports whether a failed connection attempt is worth retrying. Requests
+// the server rejects for the client's own reasons, such as authorization, are not.
+func isRetryableStreamError(err error) bool {
+	var httpErr *LaplaceHTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.HTTPStatus == http.StatusTooManyRequests || httpErr.HTTPStatus >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// backfill delivers the news published since the latest item the stream delivered, oldest
+// first, so that a reconnect leaves no gap. A failed backfill is logged and streaming carries
+// on; one that hits the page limit delivers a NewsBackfillTruncatedError. It reports whether
+// the stream is still open.
+func (s *NewsStream) backfill(ctx context.Context) bool {
+	from := s.dedup.latestTimestamp()
+	if from.IsZero() {
+		return true
+	}
+
+	size := newsBackfillPageSize
+	params := GetNewsParams{
+		Region:           s.params.Region,
+		Locale:           s.params.Locale,
+		Lane:             s.params.Lane,
+		Size:             &size,
+		OrderBy:          NewsOrderByTimestamp,
+		OrderByDirection: SortDirectionAsc,
+		Symbols:          strings.Join(s.params.Symbols, ","),
+		CategoryIds:      strings.Join(s.params.CategoryIds, ","),
+		SectorIds:        strings.Join(s.params.SectorIds, ","),
+		IndustryIds:      strings.Join(s.params.IndustryIds, ","),
+		ApiSource:        strings.Join(s.params.ApiSource, ","),
+	}
+
+	// Pages are walked with a fixed TimestampFrom: in ascending order news published meanwhile
+	// lands after the pages already read, and items repeated from before the gap are dropped
+	// as duplicates
+	params.TimestampFrom = from.UTC().Format(time.RFC3339)
+	for page := 1; page <= newsBackfillMaxPages; page++ {
+		params.Page = &page
+
+		resp, err := s.c.GetNewsV2(ctx, params)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.c.logger.Warnf("failed to backfill news since %s: %v", params.TimestampFrom, err)
+			}
+			return ctx.Err() == nil
+		}
+
+		items := slices.Clone(resp.Items)
+		slices.SortStableFunc(items, func(a, b NewsV2) int {
+			return a.Timestamp.Compare(b.Timestamp)
+		})
+
+		if result, ok := s.admit(items); ok && !s.deliver(ctx, result) {
+			return false
+		}
+
+		if len(items) < size {
+			return true
+		}
+		from = items[len(items)-1].Timestamp
+	}
+
+	s.c.logger.Warnf("news backfill stopped after %d pages at %s", newsBackfillMaxPages, from)
+	return s.deliver(ctx, NewsStreamResult{Error: &NewsBackfillTruncatedError{From: from, Pages: newsBackfillMaxPages}})
+}
+
+// newsDedup remembers the IDs of the most recent news items a stream delivered, and the latest
+// timestamp among them
+type newsDedup struct {
+	mu     sync.Mutex
+	ids    map[string]struct{}
+	order  []string
+	next   int
+	latest time.Time
+}
+
+func newNewsDedup(capacity int) *newsDedup {
+	return &newsDedup{
+		ids:   make(map[string]struct{}, capacity),
+		order: make([]string, 0, capacity),
+	}
+}
+
+// filter returns the items that were not seen before and remembers them. Items without an ID
+// cannot be deduplicated and are always returned.
+func (d *newsDedup) filter(items []NewsV2) []NewsV2 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	fresh := items[:0:0]
+	for _, item := range items {
+		if item.ID != "" {
+			if _, ok := d.ids[item.ID]; ok {
+				continue
+			}
+			d.remember(item.ID)
+		}
+		if item.Timestamp.After(d.latest) {
+			d.latest = item.Timestamp
+		}
+		fresh = append(fresh, item)
+	}
+
+	return fresh
+}
+
+// remember adds id to the set, evicting the oldest ID once the set is full
+func (d *newsDedup) remember(id string) {
+	if len(d.order) < cap(d.order) {
+		d.order = append(d.order, id)
+	} else {
+		delete(d.ids, d.order[d.next])
+		d.order[d.next] = id
+		d.next = (d.next + 1) % len(d.order)
+	}
+	d.ids[id] = struct{}{}
+}
+
+func (d *newsDedup) latestTimestamp() time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.latest
+}
+
 // GetNewsStream creates a new news stream.
 // Call Subscribe(ctx) on the returned stream to start receiving data.
 func (c *Client) GetNewsStream(params StreamNewsParams) *NewsStream {
 	return &NewsStream{
-		c:      c,
-		params: params,
-		closed: false,
+		c:            c,
+		params:       params,
+		closed:       false,
+		reconnect:    true,
+		reconnectMin: time.Second,
+		reconnectMax: DefaultNewsReconnectMaxBackoff,
+		dedup:        newNewsDedup(newsDedupCapacity),
 	}
 }
 
@@ -0,0 +1,546 @@
+package laplace
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewsFeedFormat is a syndication feed format news can be exported as.
+type NewsFeedFormat string
+
+const (
+	NewsFeedFormatRSS  NewsFeedFormat = "rss"
+	NewsFeedFormatAtom NewsFeedFormat = "atom"
+	NewsFeedFormatJSON NewsFeedFormat = "json"
+)
+
+// ContentType returns the media type a feed in the format is served with.
+func (f NewsFeedFormat) ContentType() string {
+	switch f {
+	case NewsFeedFormatAtom:
+		return "application/atom+xml; charset=utf-8"
+	case NewsFeedFormatJSON:
+		return "application/feed+json; charset=utf-8"
+	default:
+		return "application/rss+xml; charset=utf-8"
+	}
+}
+
+// NewsFeed is a syndication feed of news items, rendered with Write.
+type NewsFeed struct {
+	Title       string
+	Description string
+	// Link is the website the feed belongs to
+	Link string
+	// FeedURL is the address the feed itself is served from
+	FeedURL string
+	// ID identifies the feed in Atom; Link, then FeedURL, is used when empty
+	ID       string
+	Language string
+	// Updated defaults to the latest item's publication time
+	Updated time.Time
+	Items   []NewsFeedItem
+}
+
+// NewsFeedItem is a single feed entry.
+type NewsFeedItem struct {
+	ID         string
+	Title      string
+	Link       string
+	Summary    string
+	Author     string
+	AuthorURL  string
+	ImageURL   string
+	Published  time.Time
+	Categories []string
+}
+
+// NewsFeedItemFromNewsV2 converts a news item to a feed entry, using its content for the title
+// and summary, its publisher as the author and its tickers as categories.
+func NewsFeedItemFromNewsV2(news NewsV2) NewsFeedItem {
+	return newNewsFeedItem(news.ID, news.URL, news.ImageUrl, news.PublisherUrl, news.Publisher, news.Timestamp, news.Content, news.Tickers)
+}
+
+// NewsFeedItemFromNews converts a news item to a feed entry, using its content for the title
+// and summary, its publisher as the author and its tickers as categories.
+func NewsFeedItemFromNews(news News) NewsFeedItem {
+	tickers := append(append([]NewsTicker(nil), news.Tickers...), news.RelatedTickers...)
+	return newNewsFeedItem(news.ID, news.URL, news.ImageUrl, news.PublisherUrl, news.Publisher, news.Timestamp, news.Content, tickers)
+}
+
+func newNewsFeedItem(id, url, image, publisherURL string, publisher NewsPublisher, timestamp time.Time, content *NewsContent, tickers []NewsTicker) NewsFeedItem {
+	item := NewsFeedItem{
+		ID:        id,
+		Link:      url,
+		ImageURL:  image,
+		Author:    publisher.Name,
+		AuthorURL: publisherURL,
+		Published: timestamp,
+	}
+
+	if content != nil {
+		item.Title = content.Title
+		item.Summary = content.Description
+		if item.Summary == "" {
+			item.Summary = strings.Join(content.Summary, "\n")
+		}
+	}
+	if item.Title == "" {
+		item.Title = publisher.Name
+	}
+
+	seen := make(map[string]bool, len(tickers))
+	for _, ticker := range tickers {
+		category := ticker.Symbol
+		if category == "" {
+			category = ticker.Name
+		}
+		if category != "" && !seen[category] {
+			seen[category] = true
+			item.Categories = append(item.Categories, category)
+		}
+	}
+
+	return item
+}
+
+// Write renders the feed to w in the given format.
+func (f NewsFeed) Write(w io.Writer, format NewsFeedFormat) error {
+	switch format {
+	case NewsFeedFormatRSS:
+		return f.WriteRSS(w)
+	case NewsFeedFormatAtom:
+		return f.WriteAtom(w)
+	case NewsFeedFormatJSON:
+		return f.WriteJSONFeed(w)
+	default:
+		return fmt.Errorf("unsupported feed format: %q", format)
+	}
+}
+
+func (f NewsFeed) updated() time.Time {
+	if !f.Updated.IsZero() {
+		return f.Updated
+	}
+
+	var updated time.Time
+	for _, item := range f.Items {
+		if item.Published.After(updated) {
+			updated = item.Published
+		}
+	}
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	return updated
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	SelfLink      *atomLink `xml:"atom:link,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link,omitempty"`
+	Description string        `xml:"description,omitempty"`
+	Creator     string        `xml:"dc:creator,omitempty"`
+	Categories  []string      `xml:"category"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate,omitempty"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// WriteRSS renders the feed as RSS 2.0.
+func (f NewsFeed) WriteRSS(w io.Writer) error {
+	channel := rssChannel{
+		Title:         f.Title,
+		Link:          f.Link,
+		Description:   f.Description,
+		Language:      f.Language,
+		LastBuildDate: f.updated().Format(time.RFC1123Z),
+	}
+	if f.FeedURL != "" {
+		channel.SelfLink = &atomLink{Href: f.FeedURL, Rel: "self", Type: NewsFeedFormatRSS.ContentType()}
+	}
+
+	for _, item := range f.Items {
+		entry := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Summary,
+			Creator:     item.Author,
+			Categories:  item.Categories,
+			GUID:        rssGUID{Value: item.ID},
+		}
+		if !item.Published.IsZero() {
+			entry.PubDate = item.Published.Format(time.RFC1123Z)
+		}
+		if item.ImageURL != "" {
+			entry.Enclosure = &rssEnclosure{URL: item.ImageURL, Type: imageMediaType(item.ImageURL)}
+		}
+		channel.Items = append(channel.Items, entry)
+	}
+
+	return writeXML(w, rssFeed{
+		Version: "2.0",
+		DC:      "http://purl.org/dc/elements/1.1/",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: channel,
+	})
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang    string      `xml:"xml:lang,attr,omitempty"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Links      []atomLink     `xml:"link"`
+	Summary    string         `xml:"summary,omitempty"`
+	Author     *atomAuthor    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// WriteAtom renders the feed as Atom 1.0. Atom requires a feed id, so one of ID, Link or
+// FeedURL must be set.
+func (f NewsFeed) WriteAtom(w io.Writer) error {
+	id := f.ID
+	if id == "" {
+		id = f.Link
+	}
+	if id == "" {
+		id = f.FeedURL
+	}
+	if id == "" {
+		return errors.New("atom feed needs an ID, Link or FeedURL")
+	}
+
+	feed := atomFeed{
+		Lang:    f.Language,
+		Title:   f.Title,
+		ID:      id,
+		Updated: f.updated().Format(time.RFC3339),
+	}
+	if f.Link != "" {
+		feed.Links = append(feed.Links, atomLink{Href: f.Link, Rel: "alternate"})
+	}
+	if f.FeedURL != "" {
+		feed.Links = append(feed.Links, atomLink{Href: f.FeedURL, Rel: "self", Type: NewsFeedFormatAtom.ContentType()})
+	}
+
+	for _, item := range f.Items {
+		published := item.Published
+		if published.IsZero() {
+			published = f.updated()
+		}
+
+		entry := atomEntry{
+			Title:     item.Title,
+			ID:        "urn:laplace:news:" + item.ID,
+			Updated:   published.Format(time.RFC3339),
+			Published: published.Format(time.RFC3339),
+			Summary:   item.Summary,
+		}
+		if item.Link != "" {
+			entry.Links = append(entry.Links, atomLink{Href: item.Link, Rel: "alternate"})
+		}
+		if item.ImageURL != "" {
+			entry.Links = append(entry.Links, atomLink{Href: item.ImageURL, Rel: "enclosure", Type: imageMediaType(item.ImageURL)})
+		}
+		if item.Author != "" {
+			entry.Author = &atomAuthor{Name: item.Author, URI: item.AuthorURL}
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return writeXML(w, feed)
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url,omitempty"`
+	FeedURL     string         `json:"feed_url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Language    string         `json:"language,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url,omitempty"`
+	Title         string           `json:"title,omitempty"`
+	Summary       string           `json:"summary,omitempty"`
+	ContentText   string           `json:"content_text"`
+	Image         string           `json:"image,omitempty"`
+	DatePublished string           `json:"date_published,omitempty"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// WriteJSONFeed renders the feed as JSON Feed 1.1.
+func (f NewsFeed) WriteJSONFeed(w io.Writer) error {
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     f.FeedURL,
+		Description: f.Description,
+		Language:    f.Language,
+		Items:       make([]jsonFeedItem, 0, len(f.Items)),
+	}
+
+	for _, item := range f.Items {
+		entry := jsonFeedItem{
+			ID:          item.ID,
+			URL:         item.Link,
+			Title:       item.Title,
+			Summary:     item.Summary,
+			ContentText: item.Summary,
+			Image:       item.ImageURL,
+			Tags:        item.Categories,
+		}
+		if !item.Published.IsZero() {
+			entry.DatePublished = item.Published.Format(time.RFC3339)
+		}
+		if item.Author != "" {
+			entry.Authors = []jsonFeedAuthor{{Name: item.Author, URL: item.AuthorURL}}
+		}
+		feed.Items = append(feed.Items, entry)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(feed)
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// imageMediaType guesses an image's media type from its URL
+func imageMediaType(url string) string {
+	path, _, _ := strings.Cut(url, "?")
+	switch {
+	case strings.HasSuffix(path, ".png"):
+		return "image/png"
+	case strings.HasSuffix(path, ".gif"):
+		return "image/gif"
+	case strings.HasSuffix(path, ".webp"):
+		return "image/webp"
+	default:
+		return "image/jpeg"
+	}
+}
+
+// NewsFeedHandler serves GetNewsV2 queries as syndication feeds. The query string selects the
+// format (`format=rss|atom|json`, RSS by default) and overrides the handler's default query
+// with any of region, locale, lane, symbols, categoryIds, sectorIds, industryIds, apiSource,
+// qualityScoreMin and size, which is capped at 100 items.
+type NewsFeedHandler struct {
+	c        *Client
+	feed     NewsFeed
+	defaults GetNewsParams
+}
+
+// NewNewsFeedHandler creates a handler rendering feeds with the metadata of feed, whose items
+// are ignored, for queries starting from defaults.
+func (c *Client) NewNewsFeedHandler(feed NewsFeed, defaults GetNewsParams) *NewsFeedHandler {
+	return &NewsFeedHandler{c: c, feed: feed, defaults: defaults}
+}
+
+func (h *NewsFeedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	format := NewsFeedFormat(q.Get("format"))
+	switch format {
+	case "":
+		format = NewsFeedFormatRSS
+	case NewsFeedFormatRSS, NewsFeedFormatAtom, NewsFeedFormatJSON:
+	default:
+		http.Error(w, fmt.Sprintf("unsupported feed format: %q", format), http.StatusBadRequest)
+		return
+	}
+
+	params, err := h.params(q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.c.GetNewsV2(r.Context(), params)
+	if err != nil {
+		status := http.StatusBadGateway
+		var httpErr *LaplaceHTTPError
+		if errors.As(err, &httpErr) && httpErr.HTTPStatus == http.StatusBadRequest {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, fmt.Sprintf("failed to fetch news: %v", err), status)
+		return
+	}
+
+	feed := h.feed
+	feed.FeedURL = requestURL(r)
+	if feed.Language == "" {
+		feed.Language = string(params.Locale)
+	}
+	feed.Items = make([]NewsFeedItem, len(resp.Items))
+	for i, news := range resp.Items {
+		feed.Items[i] = NewsFeedItemFromNewsV2(news)
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	if err := feed.Write(w, format); err != nil {
+		h.c.logger.Errorf("failed to write news feed: %v", err)
+	}
+}
+
+// newsFeedMaxSize caps the number of items a single feed request fetches
+const newsFeedMaxSize = 100
+
+// params applies the request's query overrides to the handler's default query
+func (h *NewsFeedHandler) params(q map[string][]string) (GetNewsParams, error) {
+	get := func(key string) string {
+		if values := q[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	params := h.defaults
+	params.OrderBy = NewsOrderByTimestamp
+	params.OrderByDirection = SortDirectionDesc
+	params.Page = nil
+
+	if v := get("region"); v != "" {
+		params.Region = Region(v)
+	}
+	if v := get("locale"); v != "" {
+		params.Locale = Locale(v)
+	}
+	if v := get("lane"); v != "" {
+		params.Lane = NewsLane(v)
+	}
+	if v := get("symbols"); v != "" {
+		params.Symbols = v
+	}
+	if v := get("categoryIds"); v != "" {
+		params.CategoryIds = v
+	}
+	if v := get("sectorIds"); v != "" {
+		params.SectorIds = v
+	}
+	if v := get("industryIds"); v != "" {
+		params.IndustryIds = v
+	}
+	if v := get("apiSource"); v != "" {
+		params.ApiSource = v
+	}
+	if v := get("qualityScoreMin"); v != "" {
+		score, err := strconv.Atoi(v)
+		if err != nil {
+			return params, fmt.Errorf("invalid qualityScoreMin: %q", v)
+		}
+		params.QualityScoreMin = &score
+	}
+	if v := get("size"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil || size <= 0 {
+			return params, fmt.Errorf("invalid size: %q", v)
+		}
+		size = min(size, newsFeedMaxSize)
+		params.Size = &size
+	}
+
+	return params, nil
+}
+
+// requestURL reconstructs the absolute URL a request was made to
+func requestURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := r.Header.Get("X-Forwarded-Proto"); forwarded != "" {
+		scheme = forwarded
+	}
+	return scheme + "://" + r.Host + r.URL.RequestURI()
+}
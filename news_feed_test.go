@@ -0,0 +1,168 @@
+package laplace
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testFeedNews() NewsV2 {
+	return NewsV2{
+		ID:           "n1",
+		URL:          "https://example.com/n1",
+		ImageUrl:     "https://example.com/n1.png",
+		Timestamp:    time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		PublisherUrl: "https://reuters.com",
+		Publisher:    NewsPublisher{Name: "Reuters"},
+		Tickers:      []NewsTicker{{Symbol: "AAPL"}, {Name: "Microsoft"}, {Symbol: "AAPL"}},
+		Content:      &NewsContent{Title: "Apple & Microsoft <rally>", Summary: []string{"Both rose.", "Markets up."}},
+	}
+}
+
+func TestNewsFeedItemFromNewsV2(t *testing.T) {
+	item := NewsFeedItemFromNewsV2(testFeedNews())
+	require.Equal(t, NewsFeedItem{
+		ID:         "n1",
+		Title:      "Apple & Microsoft <rally>",
+		Link:       "https://example.com/n1",
+		Summary:    "Both rose.\nMarkets up.",
+		Author:     "Reuters",
+		AuthorURL:  "https://reuters.com",
+		ImageURL:   "https://example.com/n1.png",
+		Published:  time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Categories: []string{"AAPL", "Microsoft"},
+	}, item)
+
+	item = NewsFeedItemFromNews(News{ID: "n2", Publisher: NewsPublisher{Name: "Bloomberg"}, RelatedTickers: []NewsTicker{{Symbol: "TSLA"}}})
+	require.Equal(t, "Bloomberg", item.Title)
+	require.Equal(t, []string{"TSLA"}, item.Categories)
+}
+
+func TestNewsFeedFormats(t *testing.T) {
+	feed := NewsFeed{
+		Title:   "Markets",
+		Link:    "https://example.com",
+		FeedURL: "https://example.com/feed",
+		Items:   []NewsFeedItem{NewsFeedItemFromNewsV2(testFeedNews())},
+	}
+
+	var rss bytes.Buffer
+	require.NoError(t, feed.Write(&rss, NewsFeedFormatRSS))
+	var parsedRSS struct {
+		Channel struct {
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Title      string   `xml:"title"`
+				Categories []string `xml:"category"`
+				GUID       string   `xml:"guid"`
+				PubDate    string   `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	require.NoError(t, xml.Unmarshal(rss.Bytes(), &parsedRSS))
+	require.Equal(t, "Sat, 01 Mar 2025 12:00:00 +0000", parsedRSS.Channel.LastBuildDate)
+	require.Len(t, parsedRSS.Channel.Items, 1)
+	require.Equal(t, "Apple & Microsoft <rally>", parsedRSS.Channel.Items[0].Title)
+	require.Equal(t, []string{"AAPL", "Microsoft"}, parsedRSS.Channel.Items[0].Categories)
+	require.Equal(t, "n1", parsedRSS.Channel.Items[0].GUID)
+
+	var atom bytes.Buffer
+	require.NoError(t, feed.Write(&atom, NewsFeedFormatAtom))
+	var parsedAtom struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		ID      string   `xml:"id"`
+		Entries []struct {
+			ID     string `xml:"id"`
+			Author struct {
+				Name string `xml:"name"`
+			} `xml:"author"`
+			Categories []struct {
+				Term string `xml:"term,attr"`
+			} `xml:"category"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(atom.Bytes(), &parsedAtom))
+	require.Equal(t, "https://example.com", parsedAtom.ID)
+	require.Len(t, parsedAtom.Entries, 1)
+	require.Equal(t, "urn:laplace:news:n1", parsedAtom.Entries[0].ID)
+	require.Equal(t, "Reuters", parsedAtom.Entries[0].Author.Name)
+	require.Len(t, parsedAtom.Entries[0].Categories, 2)
+
+	var jsonBuf bytes.Buffer
+	require.NoError(t, feed.Write(&jsonBuf, NewsFeedFormatJSON))
+	var parsedJSON jsonFeed
+	require.NoError(t, json.Unmarshal(jsonBuf.Bytes(), &parsedJSON))
+	require.Equal(t, "https://jsonfeed.org/version/1.1", parsedJSON.Version)
+	require.Len(t, parsedJSON.Items, 1)
+	require.Equal(t, "2025-03-01T12:00:00Z", parsedJSON.Items[0].DatePublished)
+	require.Equal(t, []jsonFeedAuthor{{Name: "Reuters", URL: "https://reuters.com"}}, parsedJSON.Items[0].Authors)
+
+	require.Error(t, feed.Write(&jsonBuf, "csv"))
+
+	feed.Link = ""
+	atom.Reset()
+	require.NoError(t, feed.WriteAtom(&atom))
+	require.NoError(t, xml.Unmarshal(atom.Bytes(), &parsedAtom))
+	require.Equal(t, "https://example.com/feed", parsedAtom.ID)
+
+	feed.FeedURL = ""
+	require.Error(t, feed.WriteAtom(io.Discard))
+}
+
+func TestNewsFeedHandler(t *testing.T) {
+	var query map[string][]string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v2/news", r.URL.Path)
+		query = r.URL.Query()
+		json.NewEncoder(w).Encode(PaginatedResponse[NewsV2]{RecordCount: 1, Items: []NewsV2{testFeedNews()}})
+	}))
+	defer api.Close()
+
+	client := newOfflineTestClient(t, api.URL)
+	size := 20
+	handler := client.NewNewsFeedHandler(NewsFeed{Title: "Markets", Link: "https://example.com"}, GetNewsParams{
+		Region: RegionUs,
+		Locale: LocaleEn,
+		Lane:   NewsLaneFastMovers,
+		Size:   &size,
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/feed?format=atom&symbols=AAPL,MSFT&size=5", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, NewsFeedFormatAtom.ContentType(), rec.Header().Get("Content-Type"))
+	require.Contains(t, rec.Body.String(), `<link href="http://example.com/feed?format=atom&amp;symbols=AAPL,MSFT&amp;size=5" rel="self"`)
+
+	require.Equal(t, []string{"us"}, query["region"])
+	require.Equal(t, []string{"fast_movers"}, query["lane"])
+	require.Equal(t, []string{"AAPL,MSFT"}, query["symbols"])
+	require.Equal(t, []string{"5"}, query["size"])
+	require.Equal(t, []string{"timestamp"}, query["orderBy"])
+	require.Equal(t, []string{"desc"}, query["orderByDirection"])
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/feed", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "application/rss+xml"))
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/feed?format=csv", nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/feed?size=zero", nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/feed?size=100000", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, []string{"100"}, query["size"])
+}
@@ -0,0 +1,147 @@
+package laplace
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// NewsPredicate reports whether a news item should be delivered. Predicates run locally on
+// every item, on top of the server-side filters of StreamNewsParams.
+type NewsPredicate func(news NewsV2) bool
+
+// NewsPriceSource provides the latest price of a ticker, e.g. a LivePriceCache.
+type NewsPriceSource interface {
+	LatestPrice(symbol string) (float64, bool)
+}
+
+// NewsMinQualityScore accepts news with a quality score of at least score.
+func NewsMinQualityScore(score int64) NewsPredicate {
+	return func(news NewsV2) bool {
+		return news.QualityScore >= score
+	}
+}
+
+// NewsKeywords accepts news whose title, description or summary contains any of the keywords,
+// ignoring case the way the news index does, so that "istanbul" matches "İstanbul".
+func NewsKeywords(keywords ...string) NewsPredicate {
+	folded := make([]string, len(keywords))
+	for i, keyword := range keywords {
+		folded[i] = foldNewsText(keyword)
+	}
+
+	return func(news NewsV2) bool {
+		text := foldNewsText(newsText(news))
+		return slices.ContainsFunc(folded, func(keyword string) bool {
+			return strings.Contains(text, keyword)
+		})
+	}
+}
+
+// NewsMatches accepts news whose title, description or summary matches re.
+func NewsMatches(re *regexp.Regexp) NewsPredicate {
+	return func(news NewsV2) bool {
+		return re.MatchString(newsText(news))
+	}
+}
+
+// NewsPublishers accepts news from the given publishers only, compared by name ignoring case.
+func NewsPublishers(names ...string) NewsPredicate {
+	return func(news NewsV2) bool {
+		return containsFold(names, news.Publisher.Name)
+	}
+}
+
+// NewsExcludePublishers drops news from the given publishers, compared by name ignoring case.
+func NewsExcludePublishers(names ...string) NewsPredicate {
+	return func(news NewsV2) bool {
+		return !containsFold(names, news.Publisher.Name)
+	}
+}
+
+// NewsTickersIn accepts news mentioning at least one of the given symbols, e.g. a watchlist.
+func NewsTickersIn(symbols ...string) NewsPredicate {
+	return func(news NewsV2) bool {
+		return slices.ContainsFunc(news.Tickers, func(ticker NewsTicker) bool {
+			return ticker.Symbol != "" && containsFold(symbols, ticker.Symbol)
+		})
+	}
+}
+
+// AddFilter attaches local predicates to the stream. Only news accepted by every predicate is
+// delivered, live and backfilled alike.
+func (s *NewsStream) AddFilter(predicates ...NewsPredicate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.predicates = append(s.predicates, predicates...)
+}
+
+// SetPriceSource enables enrichment: every delivered result carries, in Prices, the latest
+// price of each ticker its news mention, as known to source when the news is received.
+func (s *NewsStream) SetPriceSource(source NewsPriceSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prices = source
+}
+
+// admit drops the news items that were delivered before or fail the stream's predicates, and
+// enriches the rest. It reports false when no item is left to deliver.
+func (s *NewsStream) admit(items []NewsV2) (NewsStreamResult, bool) {
+	s.mu.RLock()
+	predicates, source := s.predicates, s.prices
+	s.mu.RUnlock()
+
+	items = s.dedup.filter(items)
+	items = slices.DeleteFunc(items, func(news NewsV2) bool {
+		return slices.ContainsFunc(predicates, func(accept NewsPredicate) bool {
+			return !accept(news)
+		})
+	})
+	if len(items) == 0 {
+		return NewsStreamResult{}, false
+	}
+
+	result := NewsStreamResult{Data: items}
+	if source != nil {
+		result.Prices = newsPrices(items, source)
+	}
+
+	return result, true
+}
+
+// newsPrices looks up the latest price of every ticker mentioned by items
+func newsPrices(items []NewsV2, source NewsPriceSource) map[string]float64 {
+	var prices map[string]float64
+	for _, news := range items {
+		for _, ticker := range news.Tickers {
+			if ticker.Symbol == "" {
+				continue
+			}
+			if price, ok := source.LatestPrice(ticker.Symbol); ok {
+				if prices == nil {
+					prices = make(map[string]float64)
+				}
+				prices[ticker.Symbol] = price
+			}
+		}
+	}
+	return prices
+}
+
+// newsText joins the text fields keyword and regex predicates match against
+func newsText(news NewsV2) string {
+	if news.Content == nil {
+		return ""
+	}
+
+	parts := append([]string{news.Content.Title, news.Content.Description}, news.Content.Summary...)
+	return strings.Join(parts, "\n")
+}
+
+func containsFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
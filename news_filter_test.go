@@ -0,0 +1,99 @@
+package laplace
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewsPredicates(t *testing.T) {
+	news := NewsV2{
+		QualityScore: 7,
+		Publisher:    NewsPublisher{Name: "Reuters"},
+		Tickers:      []NewsTicker{{Symbol: "AAPL"}, {Name: "no symbol"}},
+		Content: &NewsContent{
+			Title:   "Apple beats estimates",
+			Summary: []string{"Revenue rose 8% on iPhone demand"},
+		},
+	}
+
+	require.True(t, NewsMinQualityScore(7)(news))
+	require.False(t, NewsMinQualityScore(8)(news))
+
+	require.True(t, NewsKeywords("IPHONE", "tesla")(news))
+	require.False(t, NewsKeywords("tesla")(news))
+	require.False(t, NewsKeywords("apple")(NewsV2{}))
+
+	// Turkish dotted and dotless I fold alike, composed or not
+	turkish := NewsV2{Content: &NewsContent{Title: "İSTANBUL borsasında IŞIK yükseldi", Summary: []string{"I\u0307zmir"}}}
+	require.True(t, NewsKeywords("istanbul")(turkish))
+	require.True(t, NewsKeywords("borsası")(turkish))
+	require.True(t, NewsKeywords("ışık")(turkish))
+	require.True(t, NewsKeywords("İzmir")(turkish))
+	require.False(t, NewsKeywords("ankara")(turkish))
+
+	require.True(t, NewsMatches(regexp.MustCompile(`rose \d+%`))(news))
+	require.False(t, NewsMatches(regexp.MustCompile(`fell \d+%`))(news))
+
+	require.True(t, NewsPublishers("reuters", "Bloomberg")(news))
+	require.False(t, NewsPublishers("Bloomberg")(news))
+	require.False(t, NewsExcludePublishers("REUTERS")(news))
+	require.True(t, NewsExcludePublishers("Bloomberg")(news))
+
+	require.True(t, NewsTickersIn("msft", "aapl")(news))
+	require.False(t, NewsTickersIn("MSFT")(news))
+}
+
+type staticPrices map[string]float64
+
+func (p staticPrices) LatestPrice(symbol string) (float64, bool) {
+	price, ok := p[symbol]
+	return price, ok
+}
+
+func TestNewsStreamAdmit(t *testing.T) {
+	client := newOfflineTestClient(t, "http://localhost")
+	stream := client.GetNewsStream(StreamNewsParams{Region: RegionUs, Locale: LocaleEn})
+	stream.AddFilter(NewsMinQualityScore(5), NewsTickersIn("AAPL", "MSFT"))
+	stream.SetPriceSource(staticPrices{"AAPL": 190.5})
+
+	result, ok := stream.admit([]NewsV2{
+		{ID: "1", QualityScore: 9, Tickers: []NewsTicker{{Symbol: "AAPL"}, {Symbol: "MSFT"}}},
+		{ID: "2", QualityScore: 2, Tickers: []NewsTicker{{Symbol: "AAPL"}}},
+		{ID: "3", QualityScore: 9, Tickers: []NewsTicker{{Symbol: "TSLA"}}},
+	})
+	require.True(t, ok)
+	require.Len(t, result.Data, 1)
+	require.Equal(t, "1", result.Data[0].ID)
+	require.Equal(t, map[string]float64{"AAPL": 190.5}, result.Prices)
+
+	_, ok = stream.admit([]NewsV2{{ID: "1", QualityScore: 9, Tickers: []NewsTicker{{Symbol: "AAPL"}}}})
+	require.False(t, ok)
+}
+
+func TestLivePriceCache(t *testing.T) {
+	srv, lines := newSSETestServer(t)
+	client := newOfflineTestClient(t, srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := client.GetLivePriceStreamForUS()
+	cache := stream.NewPriceCache()
+	require.NoError(t, stream.Subscribe(ctx, []string{"AAPL"}))
+	defer stream.Close()
+
+	lines <- `{"s":"AAPL","p":190.5,"d":1740414373252}`
+	<-stream.Receive()
+
+	quote, ok := cache.Quote("aapl")
+	require.True(t, ok)
+	require.Equal(t, 190.5, quote.Price)
+	require.Equal(t, time.UnixMilli(1740414373252), quote.UpdatedAt)
+
+	_, ok = cache.LatestPrice("MSFT")
+	require.False(t, ok)
+}
@@ -0,0 +1,243 @@
+package laplace
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// newsHighlightPageSize is the GetNewsV2 page size highlighted articles are looked up with
+var newsHighlightPageSize = 100
+
+// NewsHighlightResolverOptions configures a NewsHighlightResolver.
+type NewsHighlightResolverOptions struct {
+	// Region the highlights belong to, as in GetNewsHighlightsParams.Region; RegionUs by default
+	Region Region
+	// Locale of the resolved articles, LocaleEn by default
+	Locale Locale
+	// Lookback is how long before a highlight's creation its articles are searched for, 24
+	// hours by default
+	Lookback time.Duration
+	// MaxPages bounds the GetNewsV2 pages fetched per Resolve call, 20 by default
+	MaxPages int
+	// CacheSize is the number of articles kept between calls, 5000 by default
+	CacheSize int
+}
+
+// ResolvedNewsHighlight is a NewsHighlight with its sector buckets filled with articles. IDs
+// that could not be resolved are left out of the buckets and listed in Unresolved.
+type ResolvedNewsHighlight struct {
+	ID                      string
+	CreatedAt               time.Time
+	Consumer                []NewsV2
+	EnergyAndUtilities      []NewsV2
+	Finance                 []NewsV2
+	Healthcare              []NewsV2
+	IndustrialsAndMaterials []NewsV2
+	Tech                    []NewsV2
+	Other                   []NewsV2
+	Unresolved              []string
+}
+
+// NewsHighlightResolution is the result of resolving a page of highlights.
+type NewsHighlightResolution struct {
+	Highlights []ResolvedNewsHighlight
+	// Unresolved lists every ID that could not be resolved, across all highlights
+	Unresolved []string
+}
+
+// NewsHighlightResolver hydrates the article IDs of news highlights. Articles are looked up in
+// batches by paging GetNewsV2 over the time range of the highlights, and kept in a local cache
+// so that overlapping highlights are resolved without further requests.
+type NewsHighlightResolver struct {
+	c    *Client
+	opts NewsHighlightResolverOptions
+
+	mu    sync.Mutex
+	cache map[string]NewsV2
+	order []string
+}
+
+// NewNewsHighlightResolver creates a resolver with an empty cache.
+func (c *Client) NewNewsHighlightResolver(opts NewsHighlightResolverOptions) *NewsHighlightResolver {
+	if opts.Region == "" {
+		opts.Region = RegionUs
+	}
+	if opts.Locale == "" {
+		opts.Locale = LocaleEn
+	}
+	if opts.Lookback <= 0 {
+		opts.Lookback = 24 * time.Hour
+	}
+	if opts.MaxPages <= 0 {
+		opts.MaxPages = 20
+	}
+	if opts.CacheSize <= 0 {
+		opts.CacheSize = 5000
+	}
+
+	return &NewsHighlightResolver{
+		c:     c,
+		opts:  opts,
+		cache: make(map[string]NewsV2),
+	}
+}
+
+// Resolve fills the buckets of every highlight of a GetNewsHighlights page. It only fails if
+// looking up articles fails; IDs that are simply not found are reported as unresolved.
+func (r *NewsHighlightResolver) Resolve(ctx context.Context, page *PaginatedResponse[NewsHighlight]) (*NewsHighlightResolution, error) {
+	if page == nil || len(page.Items) == 0 {
+		return &NewsHighlightResolution{}, nil
+	}
+
+	// found holds this call's articles, which the cache may evict while fetch fills it
+	found := make(map[string]NewsV2)
+	wanted := make(map[string]bool)
+	from, to := page.Items[0].CreatedAt, page.Items[0].CreatedAt
+	for _, highlight := range page.Items {
+		for _, ids := range highlightBuckets(highlight) {
+			for _, id := range ids {
+				if news, ok := r.cached(id); ok {
+					found[id] = news
+				} else {
+					wanted[id] = true
+				}
+			}
+		}
+		from = minTime(from, highlight.CreatedAt)
+		to = maxTime(to, highlight.CreatedAt)
+	}
+
+	if len(wanted) > 0 {
+		if err := r.fetch(ctx, wanted, found, from.Add(-r.opts.Lookback), to); err != nil {
+			return nil, err
+		}
+	}
+
+	resolution := &NewsHighlightResolution{Highlights: make([]ResolvedNewsHighlight, len(page.Items))}
+	for i, highlight := range page.Items {
+		resolved := ResolvedNewsHighlight{ID: highlight.ID, CreatedAt: highlight.CreatedAt}
+		buckets := highlightBuckets(highlight)
+		targets := []*[]NewsV2{
+			&resolved.Consumer,
+			&resolved.EnergyAndUtilities,
+			&resolved.Finance,
+			&resolved.Healthcare,
+			&resolved.IndustrialsAndMaterials,
+			&resolved.Tech,
+			&resolved.Other,
+		}
+
+		for j, ids := range buckets {
+			for _, id := range ids {
+				if news, ok := found[id]; ok {
+					*targets[j] = append(*targets[j], news)
+				} else {
+					resolved.Unresolved = append(resolved.Unresolved, id)
+					resolution.Unresolved = append(resolution.Unresolved, id)
+				}
+			}
+		}
+
+		resolution.Highlights[i] = resolved
+	}
+
+	return resolution, nil
+}
+
+// fetch pages GetNewsV2 backwards from to until every wanted ID was found, the range is
+// exhausted or the page limit is reached, caching every article on the way. Wanted articles
+// are moved to found.
+func (r *NewsHighlightResolver) fetch(ctx context.Context, wanted map[string]bool, found map[string]NewsV2, from, to time.Time) error {
+	size := newsHighlightPageSize
+	params := GetNewsParams{
+		Region:           r.opts.Region,
+		Locale:           r.opts.Locale,
+		Size:             &size,
+		OrderBy:          NewsOrderByTimestamp,
+		OrderByDirection: SortDirectionDesc,
+		TimestampFrom:    from.UTC().Format(time.RFC3339),
+	}
+
+	// Highlights can be created slightly before the articles they list are timestamped
+	cursor := to.Add(time.Hour)
+	for page := 0; page < r.opts.MaxPages && len(wanted) > 0; page++ {
+		params.TimestampTo = cursor.UTC().Format(time.RFC3339)
+
+		resp, err := r.c.GetNewsV2(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to look up highlighted news: %w", err)
+		}
+
+		for _, news := range resp.Items {
+			r.store(news)
+			if wanted[news.ID] {
+				found[news.ID] = news
+				delete(wanted, news.ID)
+			}
+		}
+
+		if len(resp.Items) < size {
+			return nil
+		}
+		last := resp.Items[len(resp.Items)-1].Timestamp
+		if !last.Before(cursor) {
+			return nil
+		}
+		cursor = last
+	}
+
+	return nil
+}
+
+func (r *NewsHighlightResolver) cached(id string) (NewsV2, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	news, ok := r.cache[id]
+	return news, ok
+}
+
+// store caches news, evicting the oldest cached article once the cache is full
+func (r *NewsHighlightResolver) store(news NewsV2) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.cache[news.ID]; !ok {
+		if len(r.order) == r.opts.CacheSize {
+			delete(r.cache, r.order[0])
+			r.order = r.order[1:]
+		}
+		r.order = append(r.order, news.ID)
+	}
+	r.cache[news.ID] = news
+}
+
+// highlightBuckets returns the ID lists of a highlight in the field order of
+// ResolvedNewsHighlight
+func highlightBuckets(highlight NewsHighlight) [][]string {
+	return [][]string{
+		highlight.Consumer,
+		highlight.EnergyAndUtilities,
+		highlight.Finance,
+		highlight.Healthcare,
+		highlight.IndustrialsAndMaterials,
+		highlight.Tech,
+		highlight.Other,
+	}
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,133 @@
+package laplace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewsHighlightResolver(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Ten articles an hour apart before the highlight, served newest first two per page
+	var articles []NewsV2
+	for i := 0; i < 10; i++ {
+		articles = append(articles, NewsV2{ID: fmt.Sprintf("n%d", i), Timestamp: created.Add(-time.Duration(i) * time.Hour)})
+	}
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		q := r.URL.Query()
+		require.Equal(t, "us", q.Get("region"))
+		require.Equal(t, "desc", q.Get("orderByDirection"))
+		require.Equal(t, "2025-02-28T12:00:00Z", q.Get("timestampFrom"))
+
+		to, err := time.Parse(time.RFC3339, q.Get("timestampTo"))
+		require.NoError(t, err)
+
+		var page []NewsV2
+		for _, news := range articles {
+			if news.Timestamp.Before(to) && len(page) < 100 {
+				page = append(page, news)
+			}
+		}
+		if len(page) > 2 {
+			page = page[:2]
+		}
+		json.NewEncoder(w).Encode(PaginatedResponse[NewsV2]{Items: page})
+	}))
+	defer srv.Close()
+
+	client := newOfflineTestClient(t, srv.URL)
+	resolver := client.NewNewsHighlightResolver(NewsHighlightResolverOptions{MaxPages: 3})
+
+	page := &PaginatedResponse[NewsHighlight]{Items: []NewsHighlight{
+		{ID: "h1", CreatedAt: created, Tech: []string{"n1", "n0"}, Finance: []string{"n3", "missing"}},
+	}}
+
+	// "missing" is never found, so the lookup stops at MaxPages
+	resolution, err := resolveWithPageSize(t, resolver, page, 2)
+	require.NoError(t, err)
+	require.Equal(t, 3, requests)
+
+	highlight := resolution.Highlights[0]
+	require.Equal(t, "h1", highlight.ID)
+	require.Equal(t, []string{"n1", "n0"}, newsIDs(highlight.Tech))
+	require.Equal(t, []string{"n3"}, newsIDs(highlight.Finance))
+	require.Equal(t, []string{"missing"}, highlight.Unresolved)
+	require.Equal(t, []string{"missing"}, resolution.Unresolved)
+
+	// Cached articles resolve without requests
+	requests = 0
+	resolution, err = resolver.Resolve(context.Background(), &PaginatedResponse[NewsHighlight]{Items: []NewsHighlight{
+		{ID: "h2", CreatedAt: created, Consumer: []string{"n5", "n2"}},
+	}})
+	require.NoError(t, err)
+	require.Zero(t, requests)
+	require.Equal(t, []string{"n5", "n2"}, newsIDs(resolution.Highlights[0].Consumer))
+	require.Empty(t, resolution.Unresolved)
+}
+
+func TestNewsHighlightResolverEviction(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	var served []NewsV2
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "tr", r.URL.Query().Get("region"))
+		require.Equal(t, "tr", r.URL.Query().Get("locale"))
+		json.NewEncoder(w).Encode(PaginatedResponse[NewsV2]{Items: served})
+	}))
+	defer srv.Close()
+
+	article := func(i int) NewsV2 {
+		return NewsV2{ID: fmt.Sprintf("n%d", i), Timestamp: created.Add(-time.Duration(i) * time.Hour)}
+	}
+
+	client := newOfflineTestClient(t, srv.URL)
+	resolver := client.NewNewsHighlightResolver(NewsHighlightResolverOptions{Region: RegionTr, Locale: LocaleTr, CacheSize: 2})
+
+	served = []NewsV2{article(8), article(9)}
+	_, err := resolver.Resolve(context.Background(), &PaginatedResponse[NewsHighlight]{Items: []NewsHighlight{
+		{ID: "h1", CreatedAt: created, Tech: []string{"n9"}},
+	}})
+	require.NoError(t, err)
+
+	// n8 is cached when the call starts but evicted by the articles fetched for n0
+	served = []NewsV2{article(0), article(1), article(2)}
+	resolution, err := resolver.Resolve(context.Background(), &PaginatedResponse[NewsHighlight]{Items: []NewsHighlight{
+		{ID: "h2", CreatedAt: created, Tech: []string{"n8", "n0"}},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"n8", "n0"}, newsIDs(resolution.Highlights[0].Tech))
+	require.Empty(t, resolution.Unresolved)
+
+	_, ok := resolver.cached("n8")
+	require.False(t, ok)
+}
+
+// resolveWithPageSize resolves page while the lookup page size is shrunk so that paging is
+// exercised against a small test data set
+func resolveWithPageSize(t *testing.T, resolver *NewsHighlightResolver, page *PaginatedResponse[NewsHighlight], size int) (*NewsHighlightResolution, error) {
+	t.Helper()
+
+	original := newsHighlightPageSize
+	newsHighlightPageSize = size
+	defer func() { newsHighlightPageSize = original }()
+
+	return resolver.Resolve(context.Background(), page)
+}
+
+func newsIDs(news []NewsV2) []string {
+	ids := make([]string, len(news))
+	for i, item := range news {
+		ids[i] = item.ID
+	}
+	return ids
+}
@@ -0,0 +1,409 @@
+package laplace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// BM25 ranking parameters
+const (
+	newsIndexK1 = 1.2
+	newsIndexB  = 0.75
+)
+
+// newsIndexFields are the indexed text fields of a news item with the weight a term occurrence
+// in them counts with; a title match counts more than one deep in the article body
+var newsIndexFields = []struct {
+	weight int
+	text   func(content *NewsContent) []string
+}{
+	{3, func(c *NewsContent) []string { return []string{c.Title} }},
+	{2, func(c *NewsContent) []string { return append([]string{c.Description}, c.Summary...) }},
+	{1, func(c *NewsContent) []string { return append([]string{c.InvestorInsight}, c.Content...) }},
+}
+
+// NewsIndex is an in-memory inverted index for full-text search over news that was already
+// fetched. Text is tokenized on letters and digits and folded Turkish-aware: the dotted and
+// dotless i variants (I, İ, ı, i) all match each other. NewsIndex is safe for concurrent use.
+type NewsIndex struct {
+	mu       sync.RWMutex
+	docs     map[string]NewsV2
+	postings map[string]map[string]int
+	lengths  map[string]int
+	total    int
+}
+
+// NewsSearchOptions narrows and limits a NewsIndex search.
+type NewsSearchOptions struct {
+	// Tickers keeps news mentioning any of the symbols
+	Tickers []string
+	// SectorIds and IndustryIds keep news in any of the sectors or industries
+	SectorIds   []string
+	IndustryIds []string
+	// From and To bound the news timestamp, inclusively
+	From time.Time
+	To   time.Time
+	// MatchAll requires every query term to match instead of any
+	MatchAll bool
+	// Limit caps the number of results; 0 returns all of them
+	Limit int
+}
+
+// NewsSearchResult is a news item matching a search, with its relevance score.
+type NewsSearchResult struct {
+	News  NewsV2
+	Score float64
+}
+
+// NewNewsIndex creates an empty news index.
+func NewNewsIndex() *NewsIndex {
+	return &NewsIndex{
+		docs:     make(map[string]NewsV2),
+		postings: make(map[string]map[string]int),
+		lengths:  make(map[string]int),
+	}
+}
+
+// Add indexes news items, replacing any indexed item with the same ID. Items without an ID are
+// skipped.
+func (x *NewsIndex) Add(news ...NewsV2) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	for _, item := range news {
+		if item.ID == "" {
+			continue
+		}
+		x.remove(item.ID)
+		x.add(item)
+	}
+}
+
+// Remove drops the news item with the given ID from the index.
+func (x *NewsIndex) Remove(id string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.remove(id)
+}
+
+// Len returns the number of indexed news items.
+func (x *NewsIndex) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return len(x.docs)
+}
+
+func (x *NewsIndex) add(news NewsV2) {
+	frequencies := make(map[string]int)
+	length := 0
+	if news.Content != nil {
+		for _, field := range newsIndexFields {
+			for _, text := range field.text(news.Content) {
+				for _, term := range tokenizeNews(text) {
+					frequencies[term] += field.weight
+					length += field.weight
+				}
+			}
+		}
+	}
+
+	for term, frequency := range frequencies {
+		posting, ok := x.postings[term]
+		if !ok {
+			posting = make(map[string]int)
+			x.postings[term] = posting
+		}
+		posting[news.ID] = frequency
+	}
+
+	x.docs[news.ID] = news
+	x.lengths[news.ID] = length
+	x.total += length
+}
+
+func (x *NewsIndex) remove(id string) {
+	news, ok := x.docs[id]
+	if !ok {
+		return
+	}
+
+	if news.Content != nil {
+		for _, field := range newsIndexFields {
+			for _, text := range field.text(news.Content) {
+				for _, term := range tokenizeNews(text) {
+					if posting, ok := x.postings[term]; ok {
+						delete(posting, id)
+						if len(posting) == 0 {
+							delete(x.postings, term)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	x.total -= x.lengths[id]
+	delete(x.lengths, id)
+	delete(x.docs, id)
+}
+
+// Search returns the news matching query, best match first and newest first among equal
+// scores. A query term ending in * matches every term with that prefix, which helps with
+// Turkish suffixes ("enflasyon*" matches "enflasyonun"). An empty query returns every news
+// item that passes the filters, newest first.
+func (x *NewsIndex) Search(query string, opts NewsSearchOptions) []NewsSearchResult {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var results []NewsSearchResult
+	if terms := x.queryTerms(query); len(terms) == 0 {
+		for _, news := range x.docs {
+			if opts.accepts(news) {
+				results = append(results, NewsSearchResult{News: news})
+			}
+		}
+	} else {
+		results = x.rank(terms, opts)
+	}
+
+	slices.SortFunc(results, func(a, b NewsSearchResult) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		if c := b.News.Timestamp.Compare(a.News.Timestamp); c != 0 {
+			return c
+		}
+		return strings.Compare(a.News.ID, b.News.ID)
+	})
+
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+
+	return results
+}
+
+// queryTerms resolves every query token to the index terms it matches
+func (x *NewsIndex) queryTerms(query string) [][]string {
+	var terms [][]string
+	for _, token := range strings.Fields(query) {
+		prefix := strings.HasSuffix(token, "*")
+		for _, term := range tokenizeNews(token) {
+			if !prefix {
+				terms = append(terms, []string{term})
+				continue
+			}
+
+			var matches []string
+			for indexed := range x.postings {
+				if strings.HasPrefix(indexed, term) {
+					matches = append(matches, indexed)
+				}
+			}
+			terms = append(terms, matches)
+		}
+	}
+	return terms
+}
+
+// rank scores the news matching terms with BM25
+func (x *NewsIndex) rank(terms [][]string, opts NewsSearchOptions) []NewsSearchResult {
+	if len(x.docs) == 0 {
+		return nil
+	}
+
+	averageLength := float64(x.total) / float64(len(x.docs))
+	scores := make(map[string]float64)
+	matched := make(map[string]int)
+
+	for _, alternatives := range terms {
+		// A document matching several alternatives of a prefix term counts once for MatchAll
+		seen := make(map[string]bool)
+		for _, term := range alternatives {
+			posting := x.postings[term]
+			idf := math.Log(1 + (float64(len(x.docs))-float64(len(posting))+0.5)/(float64(len(posting))+0.5))
+			for id, frequency := range posting {
+				tf := float64(frequency)
+				norm := 1 - newsIndexB + newsIndexB*float64(x.lengths[id])/averageLength
+				scores[id] += idf * tf * (newsIndexK1 + 1) / (tf + newsIndexK1*norm)
+				if !seen[id] {
+					seen[id] = true
+					matched[id]++
+				}
+			}
+		}
+	}
+
+	var results []NewsSearchResult
+	for id, score := range scores {
+		if opts.MatchAll && matched[id] < len(terms) {
+			continue
+		}
+		if news := x.docs[id]; opts.accepts(news) {
+			results = append(results, NewsSearchResult{News: news, Score: score})
+		}
+	}
+	return results
+}
+
+func (o NewsSearchOptions) accepts(news NewsV2) bool {
+	if !o.From.IsZero() && news.Timestamp.Before(o.From) {
+		return false
+	}
+	if !o.To.IsZero() && news.Timestamp.After(o.To) {
+		return false
+	}
+	if len(o.Tickers) > 0 && !NewsTickersIn(o.Tickers...)(news) {
+		return false
+	}
+	if len(o.SectorIds) > 0 && (news.Sectors == nil || !slices.Contains(o.SectorIds, news.Sectors.ID)) {
+		return false
+	}
+	if len(o.IndustryIds) > 0 && (news.Industries == nil || !slices.Contains(o.IndustryIds, news.Industries.ID)) {
+		return false
+	}
+	return true
+}
+
+// newsIndexFile is the on-disk form of a NewsIndex; postings are rebuilt on load
+type newsIndexFile struct {
+	Version int      `json:"version"`
+	News    []NewsV2 `json:"news"`
+}
+
+const newsIndexFileVersion = 1
+
+// WriteTo writes the indexed news to w, to be read back with ReadNewsIndex.
+func (x *NewsIndex) WriteTo(w io.Writer) (int64, error) {
+	x.mu.RLock()
+	file := newsIndexFile{Version: newsIndexFileVersion, News: make([]NewsV2, 0, len(x.docs))}
+	for _, news := range x.docs {
+		file.News = append(file.News, news)
+	}
+	x.mu.RUnlock()
+
+	slices.SortFunc(file.News, func(a, b NewsV2) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	counter := &countingWriter{w: w}
+	err := json.NewEncoder(counter).Encode(file)
+	return counter.n, err
+}
+
+// Save writes the index to path, replacing the file atomically.
+func (x *NewsIndex) Save(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := x.WriteTo(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write news index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// ReadNewsIndex reads an index written with WriteTo or Save.
+func ReadNewsIndex(r io.Reader) (*NewsIndex, error) {
+	var file newsIndexFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to read news index: %w", err)
+	}
+	if file.Version != newsIndexFileVersion {
+		return nil, fmt.Errorf("unsupported news index version: %d", file.Version)
+	}
+
+	x := NewNewsIndex()
+	x.Add(file.News...)
+	return x, nil
+}
+
+// LoadNewsIndex loads the index saved at path. A missing file yields an empty index.
+func LoadNewsIndex(path string) (*NewsIndex, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return NewNewsIndex(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadNewsIndex(file)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// tokenizeNews splits text into folded search terms
+func tokenizeNews(text string) []string {
+	var terms []string
+	var term strings.Builder
+	flush := func() {
+		if term.Len() > 0 {
+			terms = append(terms, term.String())
+			term.Reset()
+		}
+	}
+
+	for _, r := range foldNewsText(text) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		term.WriteRune(r)
+	}
+	flush()
+
+	return terms
+}
+
+// foldNewsText folds the case of text with foldNewsRune, dropping the combining dot above of a
+// decomposed İ
+func foldNewsText(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\u0307' {
+			return -1
+		}
+		return foldNewsRune(r)
+	}, text)
+}
+
+// foldNewsRune lowercases r, mapping every Turkish and Latin i variant to i so that the
+// matching works for text whatever locale it was cased with
+func foldNewsRune(r rune) rune {
+	switch r {
+	case 'I', 'İ', 'ı':
+		return 'i'
+	}
+	return unicode.ToLower(r)
+}
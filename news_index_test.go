@@ -0,0 +1,105 @@
+package laplace
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenizeNews(t *testing.T) {
+	require.Equal(t, []string{"istanbul", "borsasi", "ilk", "işlem", "thyao", "nun"}, tokenizeNews("İSTANBUL Borsası: ilk işlem, THYAO'nun"))
+	require.Equal(t, []string{"iphone", "satişlari", "2025"}, tokenizeNews("IPHONE satışları 2025"))
+	require.Equal(t, []string{"izmir"}, tokenizeNews("İzmir"))
+}
+
+func testIndexNews() []NewsV2 {
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []NewsV2{
+		{
+			ID:        "1",
+			Timestamp: at,
+			Tickers:   []NewsTicker{{Symbol: "THYAO"}},
+			Sectors:   &NewsSector{ID: "transport"},
+			Content:   &NewsContent{Title: "THY yolcu sayısını artırdı", Content: []string{"Enflasyonun etkisi sınırlı kaldı."}},
+		},
+		{
+			ID:        "2",
+			Timestamp: at.Add(time.Hour),
+			Tickers:   []NewsTicker{{Symbol: "AKBNK"}},
+			Sectors:   &NewsSector{ID: "banking"},
+			Content:   &NewsContent{Title: "ENFLASYON beklentisi yükseldi", Summary: []string{"Bankalar faiz artırımı bekliyor."}},
+		},
+		{
+			ID:        "3",
+			Timestamp: at.Add(2 * time.Hour),
+			Tickers:   []NewsTicker{{Symbol: "GARAN"}},
+			Sectors:   &NewsSector{ID: "banking"},
+			Content:   &NewsContent{Title: "Garanti bilanço açıkladı", InvestorInsight: "Faiz marjı genişledi."},
+		},
+	}
+}
+
+func searchIDs(results []NewsSearchResult) []string {
+	ids := make([]string, len(results))
+	for i, result := range results {
+		ids[i] = result.News.ID
+	}
+	return ids
+}
+
+func TestNewsIndexSearch(t *testing.T) {
+	index := NewNewsIndex()
+	index.Add(testIndexNews()...)
+	require.Equal(t, 3, index.Len())
+
+	// Case folding matches ENFLASYON, and the prefix also matches "enflasyonun" in an article
+	// body, which ranks below the title match
+	require.Equal(t, []string{"2"}, searchIDs(index.Search("enflasyon", NewsSearchOptions{})))
+	require.Equal(t, []string{"2", "1"}, searchIDs(index.Search("enflasyon*", NewsSearchOptions{})))
+
+	require.Equal(t, []string{"2", "3"}, searchIDs(index.Search("FAİZ", NewsSearchOptions{})))
+	require.Equal(t, []string{"3"}, searchIDs(index.Search("faiz", NewsSearchOptions{Tickers: []string{"garan"}})))
+	require.Equal(t, []string{"3"}, searchIDs(index.Search("faiz bilanço", NewsSearchOptions{MatchAll: true})))
+	require.Equal(t, []string{"3", "2"}, searchIDs(index.Search("faiz bilanço", NewsSearchOptions{})))
+	require.Equal(t, []string{"3"}, searchIDs(index.Search("faiz bilanço", NewsSearchOptions{Limit: 1})))
+
+	// Without a query the filters alone select, newest first
+	require.Equal(t, []string{"3", "2"}, searchIDs(index.Search("", NewsSearchOptions{SectorIds: []string{"banking"}})))
+	from := time.Date(2025, 3, 1, 13, 0, 0, 0, time.UTC)
+	require.Equal(t, []string{"3", "2"}, searchIDs(index.Search("", NewsSearchOptions{From: from})))
+	require.Empty(t, index.Search("garanti", NewsSearchOptions{To: from}))
+
+	// Re-adding an item replaces its terms
+	updated := testIndexNews()[2]
+	updated.Content = &NewsContent{Title: "Garanti temettü dağıtacak"}
+	index.Add(updated)
+	require.Equal(t, 3, index.Len())
+	require.Equal(t, []string{"2"}, searchIDs(index.Search("faiz", NewsSearchOptions{})))
+	require.Equal(t, []string{"3"}, searchIDs(index.Search("temettü", NewsSearchOptions{})))
+
+	index.Remove("3")
+	require.Empty(t, index.Search("garanti", NewsSearchOptions{}))
+}
+
+func TestNewsIndexPersistence(t *testing.T) {
+	index := NewNewsIndex()
+	index.Add(testIndexNews()...)
+
+	path := filepath.Join(t.TempDir(), "news.index")
+	require.NoError(t, index.Save(path))
+
+	loaded, err := LoadNewsIndex(path)
+	require.NoError(t, err)
+	require.Equal(t, 3, loaded.Len())
+	require.Equal(t, index.Search("faiz", NewsSearchOptions{}), loaded.Search("faiz", NewsSearchOptions{}))
+
+	empty, err := LoadNewsIndex(filepath.Join(t.TempDir(), "missing.index"))
+	require.NoError(t, err)
+	require.Zero(t, empty.Len())
+
+	_, err = ReadNewsIndex(bytes.NewReader([]byte(`{"version":99}`)))
+	require.Error(t, err)
+}
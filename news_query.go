@@ -0,0 +1,326 @@
+package laplace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// newsLaneRegions maps every news lane to the region it belongs to
+var newsLaneRegions = map[NewsLane]Region{
+	NewsLaneGlobalMacro: RegionUs,
+	NewsLaneFastMovers:  RegionUs,
+	NewsLaneTrEkonomi:   RegionTr,
+	NewsLaneBist:        RegionTr,
+}
+
+// Region returns the region a lane belongs to, or an empty region for an unknown lane.
+func (l NewsLane) Region() Region {
+	return newsLaneRegions[l]
+}
+
+// NewsQuery builds the parameters of the news endpoints and the news stream from typed values.
+// Setters can be chained and may be called repeatedly; list setters append. Call Validate, and
+// optionally ValidateCatalog, before sending the query, or rely on Params and StreamParams,
+// which validate it themselves.
+//
+//	params, err := laplace.NewNewsQuery(laplace.RegionTr, laplace.LocaleTr).
+//		Lane(laplace.NewsLaneBist).
+//		Symbols("THYAO", "GARAN").
+//		Between(from, to).
+//		Params()
+type NewsQuery struct {
+	region           Region
+	locale           Locale
+	lane             NewsLane
+	newsType         NewsType
+	page             *int
+	size             *int
+	orderBy          NewsOrderBy
+	orderByDirection SortDirection
+	symbols          []string
+	categoryIds      []string
+	sectorIds        []string
+	industryIds      []string
+	apiSources       []string
+	qualityScoreMin  *int
+	qualityScoreMax  *int
+	from             time.Time
+	to               time.Time
+}
+
+// NewNewsQuery starts a query for the given region and locale.
+func NewNewsQuery(region Region, locale Locale) *NewsQuery {
+	return &NewsQuery{region: region, locale: locale}
+}
+
+// Lane restricts the query to a lane, which must belong to the query region.
+func (q *NewsQuery) Lane(lane NewsLane) *NewsQuery {
+	q.lane = lane
+	return q
+}
+
+// NewsType restricts the query to a news type. It is not supported by the news stream.
+func (q *NewsQuery) NewsType(newsType NewsType) *NewsQuery {
+	q.newsType = newsType
+	return q
+}
+
+// Page selects a page of the given size. It is not supported by the news stream.
+func (q *NewsQuery) Page(page, size int) *NewsQuery {
+	q.page = &page
+	q.size = &size
+	return q
+}
+
+// OrderBy sets the ordering of the results. It is not supported by the news stream.
+func (q *NewsQuery) OrderBy(orderBy NewsOrderBy, direction SortDirection) *NewsQuery {
+	q.orderBy = orderBy
+	q.orderByDirection = direction
+	return q
+}
+
+// Symbols restricts the query to news mentioning any of the symbols.
+func (q *NewsQuery) Symbols(symbols ...string) *NewsQuery {
+	q.symbols = append(q.symbols, symbols...)
+	return q
+}
+
+// Categories restricts the query to news in any of the categories.
+func (q *NewsQuery) Categories(categoryIds ...string) *NewsQuery {
+	q.categoryIds = append(q.categoryIds, categoryIds...)
+	return q
+}
+
+// Sectors restricts the query to news in any of the sectors.
+func (q *NewsQuery) Sectors(sectorIds ...string) *NewsQuery {
+	q.sectorIds = append(q.sectorIds, sectorIds...)
+	return q
+}
+
+// Industries restricts the query to news in any of the industries.
+func (q *NewsQuery) Industries(industryIds ...string) *NewsQuery {
+	q.industryIds = append(q.industryIds, industryIds...)
+	return q
+}
+
+// ApiSources restricts the query to news from any of the sources.
+func (q *NewsQuery) ApiSources(sourceIds ...string) *NewsQuery {
+	q.apiSources = append(q.apiSources, sourceIds...)
+	return q
+}
+
+// QualityScore restricts the query to news with a quality score within [minScore, maxScore].
+// It is not supported by the news stream.
+func (q *NewsQuery) QualityScore(minScore, maxScore int) *NewsQuery {
+	q.qualityScoreMin = &minScore
+	q.qualityScoreMax = &maxScore
+	return q
+}
+
+// MinQualityScore restricts the query to news with at least the given quality score. It is not
+// supported by the news stream.
+func (q *NewsQuery) MinQualityScore(minScore int) *NewsQuery {
+	q.qualityScoreMin = &minScore
+	return q
+}
+
+// Between restricts the query to news published within [from, to]; a zero time leaves that
+// end open. It is not supported by the news stream.
+func (q *NewsQuery) Between(from, to time.Time) *NewsQuery {
+	q.from = from
+	q.to = to
+	return q
+}
+
+// Validate checks the query without contacting the API: the region and locale are known, the
+// lane belongs to the region and the ranges are not inverted. All problems are reported at once.
+func (q *NewsQuery) Validate() error {
+	var errs []error
+
+	if q.region != RegionTr && q.region != RegionUs {
+		errs = append(errs, fmt.Errorf("invalid news region %q, must be %q or %q", q.region, RegionTr, RegionUs))
+	}
+	if q.locale != LocaleTr && q.locale != LocaleEn {
+		errs = append(errs, fmt.Errorf("invalid news locale %q, must be %q or %q", q.locale, LocaleTr, LocaleEn))
+	}
+	if q.lane != "" {
+		if region := q.lane.Region(); region == "" {
+			errs = append(errs, fmt.Errorf("unknown news lane %q", q.lane))
+		} else if region != q.region {
+			errs = append(errs, fmt.Errorf("news lane %q belongs to region %q, not %q", q.lane, region, q.region))
+		}
+	}
+	if q.page != nil && *q.page < 1 {
+		errs = append(errs, fmt.Errorf("page must be at least 1, got %d", *q.page))
+	}
+	if q.size != nil && *q.size < 1 {
+		errs = append(errs, fmt.Errorf("page size must be at least 1, got %d", *q.size))
+	}
+	if q.orderByDirection != "" && q.orderBy == "" {
+		errs = append(errs, fmt.Errorf("order direction requires an order field"))
+	}
+	if q.qualityScoreMin != nil && q.qualityScoreMax != nil && *q.qualityScoreMin > *q.qualityScoreMax {
+		errs = append(errs, fmt.Errorf("minimum quality score %d exceeds maximum %d", *q.qualityScoreMin, *q.qualityScoreMax))
+	}
+	if !q.from.IsZero() && !q.to.IsZero() && q.from.After(q.to) {
+		errs = append(errs, fmt.Errorf("news range starts at %s, after its end %s", q.from.Format(time.RFC3339), q.to.Format(time.RFC3339)))
+	}
+	for _, list := range []struct {
+		name   string
+		values []string
+	}{
+		{"symbol", q.symbols},
+		{"category", q.categoryIds},
+		{"sector", q.sectorIds},
+		{"industry", q.industryIds},
+		{"source", q.apiSources},
+	} {
+		if slices.Contains(list.values, "") {
+			errs = append(errs, fmt.Errorf("empty %s in news query", list.name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// NewsCatalog holds the categories, lanes and sources the news filters accept for a region
+// and locale.
+type NewsCatalog struct {
+	Categories []NewsCategory
+	Lanes      []NewsLaneInfo
+	ApiSources []NewsApiSource
+}
+
+// GetNewsCatalog fetches the category, lane and source catalogs used by
+// NewsQuery.ValidateCatalog. Catalogs change rarely, so one can be reused across queries.
+func (c *Client) GetNewsCatalog(ctx context.Context, region Region, locale Locale) (*NewsCatalog, error) {
+	categories, err := c.GetNewsCategories(ctx, locale)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get news categories: %w", err)
+	}
+
+	lanes, err := c.GetNewsLanes(ctx, GetNewsLanesParams{Region: region})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get news lanes: %w", err)
+	}
+
+	sources, err := c.GetNewsApiSourceNames(ctx, GetNewsApiSourceNamesParams{Region: region, Language: locale})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get news sources: %w", err)
+	}
+
+	return &NewsCatalog{Categories: categories, Lanes: lanes, ApiSources: sources}, nil
+}
+
+// ValidateCatalog checks the lane, categories and sources of the query against a catalog.
+// Categories must be given by ID, as they are sent to the API as IDs.
+func (q *NewsQuery) ValidateCatalog(catalog *NewsCatalog) error {
+	var errs []error
+
+	if q.lane != "" && !slices.ContainsFunc(catalog.Lanes, func(lane NewsLaneInfo) bool {
+		return lane.ID == q.lane
+	}) {
+		errs = append(errs, fmt.Errorf("news lane %q is not offered", q.lane))
+	}
+	for _, id := range q.categoryIds {
+		if slices.ContainsFunc(catalog.Categories, func(category NewsCategory) bool {
+			return category.ID == id
+		}) {
+			continue
+		}
+		if i := slices.IndexFunc(catalog.Categories, func(category NewsCategory) bool {
+			return category.Name == id
+		}); i >= 0 {
+			errs = append(errs, fmt.Errorf("news category %q is given by name, use its ID %q", id, catalog.Categories[i].ID))
+			continue
+		}
+		errs = append(errs, fmt.Errorf("unknown news category %q", id))
+	}
+	for _, id := range q.apiSources {
+		if !slices.ContainsFunc(catalog.ApiSources, func(source NewsApiSource) bool {
+			return source.ID == id
+		}) {
+			errs = append(errs, fmt.Errorf("unknown news source %q", id))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Params validates the query and returns it as GetNews and GetNewsV2 parameters.
+func (q *NewsQuery) Params() (GetNewsParams, error) {
+	if err := q.Validate(); err != nil {
+		return GetNewsParams{}, err
+	}
+
+	params := GetNewsParams{
+		Region:           q.region,
+		Locale:           q.locale,
+		NewsType:         q.newsType,
+		Lane:             q.lane,
+		Page:             q.page,
+		Size:             q.size,
+		OrderBy:          q.orderBy,
+		OrderByDirection: q.orderByDirection,
+		Symbols:          strings.Join(q.symbols, ","),
+		CategoryIds:      strings.Join(q.categoryIds, ","),
+		SectorIds:        strings.Join(q.sectorIds, ","),
+		IndustryIds:      strings.Join(q.industryIds, ","),
+		ApiSource:        strings.Join(q.apiSources, ","),
+		QualityScoreMin:  q.qualityScoreMin,
+		QualityScoreMax:  q.qualityScoreMax,
+	}
+	if !q.from.IsZero() {
+		params.TimestampFrom = q.from.UTC().Format(time.RFC3339)
+	}
+	if !q.to.IsZero() {
+		params.TimestampTo = q.to.UTC().Format(time.RFC3339)
+	}
+
+	return params, nil
+}
+
+// StreamParams validates the query and returns it as news stream parameters. Queries using
+// filters the stream does not support are rejected rather than silently widened.
+func (q *NewsQuery) StreamParams() (StreamNewsParams, error) {
+	errs := []error{q.Validate()}
+
+	var unsupported []string
+	if q.newsType != "" {
+		unsupported = append(unsupported, "news type")
+	}
+	if q.page != nil || q.size != nil {
+		unsupported = append(unsupported, "paging")
+	}
+	if q.orderBy != "" || q.orderByDirection != "" {
+		unsupported = append(unsupported, "ordering")
+	}
+	if q.qualityScoreMin != nil || q.qualityScoreMax != nil {
+		unsupported = append(unsupported, "quality score")
+	}
+	if !q.from.IsZero() || !q.to.IsZero() {
+		unsupported = append(unsupported, "time range")
+	}
+	if len(unsupported) > 0 {
+		errs = append(errs, fmt.Errorf("news stream does not support filtering by %s", strings.Join(unsupported, ", ")))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return StreamNewsParams{}, err
+	}
+
+	return StreamNewsParams{
+		Region:      q.region,
+		Locale:      q.locale,
+		Lane:        q.lane,
+		Symbols:     slices.Clone(q.symbols),
+		CategoryIds: slices.Clone(q.categoryIds),
+		SectorIds:   slices.Clone(q.sectorIds),
+		IndustryIds: slices.Clone(q.industryIds),
+		ApiSource:   slices.Clone(q.apiSources),
+	}, nil
+}
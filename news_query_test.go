@@ -0,0 +1,108 @@
+package laplace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewsQueryParams(t *testing.T) {
+	from := time.Date(2025, 3, 1, 9, 0, 0, 0, time.FixedZone("TRT", 3*60*60))
+	to := from.Add(24 * time.Hour)
+
+	params, err := NewNewsQuery(RegionTr, LocaleTr).
+		Lane(NewsLaneBist).
+		Symbols("THYAO", "GARAN").
+		Symbols("AKBNK").
+		Categories("earnings").
+		ApiSources("kap").
+		QualityScore(3, 10).
+		OrderBy(NewsOrderByTimestamp, SortDirectionDesc).
+		Page(1, 20).
+		Between(from, to).
+		Params()
+	require.NoError(t, err)
+
+	require.Equal(t, NewsLaneBist, params.Lane)
+	require.Equal(t, "THYAO,GARAN,AKBNK", params.Symbols)
+	require.Equal(t, "earnings", params.CategoryIds)
+	require.Equal(t, "kap", params.ApiSource)
+	require.Equal(t, 3, *params.QualityScoreMin)
+	require.Equal(t, 10, *params.QualityScoreMax)
+	require.Equal(t, 20, *params.Size)
+	require.Equal(t, "2025-03-01T06:00:00Z", params.TimestampFrom)
+	require.Equal(t, "2025-03-02T06:00:00Z", params.TimestampTo)
+
+	stream, err := NewNewsQuery(RegionUs, LocaleEn).
+		Lane(NewsLaneFastMovers).
+		Symbols("AAPL").
+		Sectors("tech").
+		StreamParams()
+	require.NoError(t, err)
+	require.Equal(t, StreamNewsParams{
+		Region:    RegionUs,
+		Locale:    LocaleEn,
+		Lane:      NewsLaneFastMovers,
+		Symbols:   []string{"AAPL"},
+		SectorIds: []string{"tech"},
+	}, stream)
+}
+
+func TestNewsQueryValidate(t *testing.T) {
+	err := NewNewsQuery(RegionUs, LocaleEn).Lane(NewsLaneBist).Validate()
+	require.ErrorContains(t, err, `news lane "bist" belongs to region "tr", not "us"`)
+
+	err = NewNewsQuery(RegionTr, "de").
+		Lane("sports").
+		QualityScore(8, 2).
+		Between(time.Now(), time.Now().Add(-time.Hour)).
+		Symbols("").
+		Validate()
+	require.ErrorContains(t, err, `invalid news locale "de"`)
+	require.ErrorContains(t, err, `unknown news lane "sports"`)
+	require.ErrorContains(t, err, "minimum quality score 8 exceeds maximum 2")
+	require.ErrorContains(t, err, "after its end")
+	require.ErrorContains(t, err, "empty symbol")
+
+	_, err = NewNewsQuery(RegionTr, LocaleTr).MinQualityScore(5).Between(time.Now(), time.Time{}).StreamParams()
+	require.ErrorContains(t, err, "news stream does not support filtering by quality score, time range")
+
+	_, err = NewNewsQuery(RegionNone, LocaleTr).Params()
+	require.ErrorContains(t, err, `invalid news region "none"`)
+}
+
+func TestNewsQueryValidateCatalog(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/news/categories":
+			json.NewEncoder(w).Encode([]NewsCategory{{ID: "earnings", Name: "Bilanço"}})
+		case "/api/v1/news/lanes":
+			require.Equal(t, "tr", r.URL.Query().Get("region"))
+			json.NewEncoder(w).Encode([]NewsLaneInfo{{ID: NewsLaneBist, Label: "BIST"}})
+		case "/api/v1/news/api-source-names":
+			json.NewEncoder(w).Encode([]NewsApiSource{{ID: "kap", Name: "KAP"}})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	client := newOfflineTestClient(t, srv.URL)
+	catalog, err := client.GetNewsCatalog(context.Background(), RegionTr, LocaleTr)
+	require.NoError(t, err)
+
+	query := NewNewsQuery(RegionTr, LocaleTr).Lane(NewsLaneBist).Categories("earnings").ApiSources("kap")
+	require.NoError(t, query.ValidateCatalog(catalog))
+
+	query = NewNewsQuery(RegionTr, LocaleTr).Lane(NewsLaneTrEkonomi).Categories("sports", "Bilanço").ApiSources("rss")
+	err = query.ValidateCatalog(catalog)
+	require.ErrorContains(t, err, `news category "Bilanço" is given by name, use its ID "earnings"`)
+	require.ErrorContains(t, err, `news lane "tr_ekonomi" is not offered`)
+	require.ErrorContains(t, err, `unknown news category "sports"`)
+	require.ErrorContains(t, err, `unknown news source "rss"`)
+}
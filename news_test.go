@@ -2,9 +2,15 @@ package laplace
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -211,3 +217,189 @@ func (s *NewsTestSuite) TestGetNewsStream() {
 		s.T().Log("Timeout waiting for stream data")
 	}
 }
+
+func TestNewsDedup(t *testing.T) {
+	dedup := newNewsDedup(2)
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	fresh := dedup.filter([]NewsV2{{ID: "1", Timestamp: at}, {ID: "2", Timestamp: at.Add(time.Minute)}, {ID: "1"}})
+	require.Len(t, fresh, 2)
+	require.Equal(t, at.Add(time.Minute), dedup.latestTimestamp())
+
+	// A third ID evicts the oldest one
+	require.Len(t, dedup.filter([]NewsV2{{ID: "3"}, {ID: "2"}}), 1)
+	require.Len(t, dedup.filter([]NewsV2{{ID: "1"}}), 1)
+	require.Equal(t, at.Add(time.Minute), dedup.latestTimestamp())
+}
+
+func TestNewsStreamReconnectBackfill(t *testing.T) {
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	news := func(id string, offset time.Duration) NewsV2 {
+		return NewsV2{ID: id, Timestamp: at.Add(offset)}
+	}
+	encode := func(items ...NewsV2) string {
+		data, err := json.Marshal(items)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	var connections atomic.Int32
+	backfillQuery := make(chan map[string]string, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/news/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+
+		if connections.Add(1) == 1 {
+			fmt.Fprintf(w, "data:%s\n\n", encode(news("1", 0), news("2", time.Minute)))
+			return
+		}
+
+		fmt.Fprintf(w, "data:%s\n\n", encode(news("2", time.Minute), news("4", 3*time.Minute)))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	mux.HandleFunc("/api/v2/news", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		backfillQuery <- map[string]string{
+			"timestampFrom":    q.Get("timestampFrom"),
+			"orderBy":          q.Get("orderBy"),
+			"orderByDirection": q.Get("orderByDirection"),
+			"symbols":          q.Get("symbols"),
+		}
+		json.NewEncoder(w).Encode(PaginatedResponse[NewsV2]{
+			RecordCount: 2,
+			Items:       []NewsV2{news("3", 2*time.Minute), news("2", time.Minute)},
+		})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	stats := NewStreamStats(time.Minute)
+	client, err := NewClient(LaplaceConfiguration{APIKey: "test", BaseURL: srv.URL}, WithStreamMetrics(stats))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := client.GetNewsStream(StreamNewsParams{Region: RegionUs, Locale: LocaleEn, Symbols: []string{"AAPL", "MSFT"}})
+	stream.reconnectMin = 10 * time.Millisecond
+	require.NoError(t, stream.Subscribe(ctx))
+	defer stream.Close()
+
+	var ids []string
+	for len(ids) < 4 {
+		select {
+		case data := <-stream.Receive():
+			require.NoError(t, data.Error)
+			for _, item := range data.Data {
+				ids = append(ids, item.ID)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out with %v", ids)
+		}
+	}
+
+	require.Equal(t, []string{"1", "2", "3", "4"}, ids)
+	require.Equal(t, map[string]string{
+		"timestampFrom":    "2025-03-01T12:01:00Z",
+		"orderBy":          "timestamp",
+		"orderByDirection": "asc",
+		"symbols":          "AAPL,MSFT",
+	}, <-backfillQuery)
+	require.Equal(t, int64(1), stats.Snapshot()[newsStreamName(RegionUs)].Reconnects)
+}
+
+func TestNewsStreamBackfillPages(t *testing.T) {
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// newServer streams one item, drops the connection and then streams item "live"; the
+	// backfill pages hold total items published within the same second
+	newServer := func(t *testing.T, total int) (*httptest.Server, *[]string) {
+		var connections atomic.Int32
+		var timestamps []string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/v1/news/stream", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+
+			if connections.Add(1) == 1 {
+				fmt.Fprintf(w, `data:[{"id":"first","timestamp":%q}]`+"\n\n", at.Format(time.RFC3339))
+				return
+			}
+
+			fmt.Fprintf(w, `data:[{"id":"live","timestamp":%q}]`+"\n\n", at.Add(time.Minute).Format(time.RFC3339))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		})
+		mux.HandleFunc("/api/v2/news", func(w http.ResponseWriter, r *http.Request) {
+			timestamps = append(timestamps, r.URL.Query().Get("timestampFrom"))
+			var page, size int
+			fmt.Sscan(r.URL.Query().Get("page"), &page)
+			fmt.Sscan(r.URL.Query().Get("size"), &size)
+
+			var items []NewsV2
+			for i := (page - 1) * size; i < min(page*size, total); i++ {
+				items = append(items, NewsV2{ID: fmt.Sprint(i), Timestamp: at.Add(500 * time.Millisecond)})
+			}
+			json.NewEncoder(w).Encode(PaginatedResponse[NewsV2]{RecordCount: total, Items: items})
+		})
+		srv := httptest.NewServer(mux)
+		t.Cleanup(srv.Close)
+		return srv, &timestamps
+	}
+
+	receive := func(t *testing.T, srv *httptest.Server) ([]string, []error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		stream := newOfflineTestClient(t, srv.URL).GetNewsStream(StreamNewsParams{Region: RegionUs, Locale: LocaleEn})
+		stream.reconnectMin = 10 * time.Millisecond
+		require.NoError(t, stream.Subscribe(ctx))
+		defer stream.Close()
+
+		var ids []string
+		var errs []error
+		for len(ids) == 0 || ids[len(ids)-1] != "live" {
+			select {
+			case data := <-stream.Receive():
+				if data.Error != nil {
+					errs = append(errs, data.Error)
+				}
+				for _, item := range data.Data {
+					ids = append(ids, item.ID)
+				}
+			case <-ctx.Done():
+				t.Fatalf("timed out after %d items", len(ids))
+			}
+		}
+		return ids, errs
+	}
+
+	t.Run("same second", func(t *testing.T) {
+		srv, timestamps := newServer(t, 2*newsBackfillPageSize+50)
+		ids, errs := receive(t, srv)
+
+		require.Empty(t, errs)
+		require.Len(t, ids, 2*newsBackfillPageSize+52)
+		require.Equal(t, "0", ids[1])
+		require.Equal(t, fmt.Sprint(2*newsBackfillPageSize+49), ids[len(ids)-2])
+		require.Equal(t, []string{"2025-03-01T12:00:00Z", "2025-03-01T12:00:00Z", "2025-03-01T12:00:00Z"}, *timestamps)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		srv, timestamps := newServer(t, (newsBackfillMaxPages+1)*newsBackfillPageSize)
+		ids, errs := receive(t, srv)
+
+		require.Len(t, *timestamps, newsBackfillMaxPages)
+		require.Len(t, ids, newsBackfillMaxPages*newsBackfillPageSize+2)
+		require.Len(t, errs, 1)
+		require.True(t, IsRecoverable(errs[0]))
+
+		var truncatedErr *NewsBackfillTruncatedError
+		require.ErrorAs(t, errs[0], &truncatedErr)
+		require.Equal(t, newsBackfillMaxPages, truncatedErr.Pages)
+		require.Equal(t, at.Add(500*time.Millisecond), truncatedErr.From.UTC())
+	})
+}
@@ -0,0 +1,320 @@
+package laplace
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewsTrendKind is the dimension news mentions are counted along.
+type NewsTrendKind string
+
+const (
+	NewsTrendTicker   NewsTrendKind = "ticker"
+	NewsTrendSector   NewsTrendKind = "sector"
+	NewsTrendCategory NewsTrendKind = "category"
+)
+
+// newsTrendsMaxPages bounds a NewsTrends backfill
+const newsTrendsMaxPages = 100
+
+// NewsTrendOptions configures a NewsTrends aggregator.
+type NewsTrendOptions struct {
+	// Resolution is the granularity mentions are counted at, 5 minutes by default. Windows
+	// are rounded to it.
+	Resolution time.Duration
+	// Retention is how much history is kept for baselines, 7 days by default
+	Retention time.Duration
+	// MinMentions is the number of mentions in the window a key needs to be listed as
+	// trending, 3 by default
+	MinMentions int
+}
+
+// NewsTrend is a ticker, sector or category with its news volume in a window, compared to the
+// baseline of the windows before it.
+type NewsTrend struct {
+	Kind     NewsTrendKind
+	Key      string
+	Name     string
+	Mentions int
+	// BaselineMean and BaselineStdDev describe the mention counts of the preceding windows
+	BaselineMean   float64
+	BaselineStdDev float64
+	// ZScore is the number of standard deviations Mentions lies above the baseline mean. The
+	// standard deviation is floored at 1 so that rare keys do not trend on a single mention.
+	ZScore float64
+}
+
+type newsTrendKey struct {
+	kind NewsTrendKind
+	key  string
+}
+
+// NewsTrends keeps rolling news mention counts per ticker, sector and category, fed from
+// GetNewsV2 results or a NewsStream, and ranks what is unusually busy. It is safe for
+// concurrent use.
+type NewsTrends struct {
+	mu     sync.Mutex
+	opts   NewsTrendOptions
+	now    func() time.Time
+	counts map[newsTrendKey]map[int64]int
+	names  map[newsTrendKey]string
+	seen   map[string]time.Time
+	pruned int64
+}
+
+// NewNewsTrends creates an empty aggregator.
+func NewNewsTrends(opts NewsTrendOptions) *NewsTrends {
+	if opts.Resolution <= 0 {
+		opts.Resolution = 5 * time.Minute
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = 7 * 24 * time.Hour
+	}
+	if opts.MinMentions <= 0 {
+		opts.MinMentions = 3
+	}
+
+	return &NewsTrends{
+		opts:   opts,
+		now:    time.Now,
+		counts: make(map[newsTrendKey]map[int64]int),
+		names:  make(map[newsTrendKey]string),
+		seen:   make(map[string]time.Time),
+	}
+}
+
+// Add counts the mentions of news items. Items already counted, by ID, and items older than
+// the retention are ignored.
+func (t *NewsTrends) Add(news ...NewsV2) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cutoff := t.now().Add(-t.opts.Retention)
+	for _, item := range news {
+		if item.Timestamp.Before(cutoff) {
+			continue
+		}
+		if item.ID != "" {
+			if _, ok := t.seen[item.ID]; ok {
+				continue
+			}
+			t.seen[item.ID] = item.Timestamp
+		}
+
+		bucket := t.bucket(item.Timestamp)
+		for key, name := range newsTrendKeys(item) {
+			counts, ok := t.counts[key]
+			if !ok {
+				counts = make(map[int64]int)
+				t.counts[key] = counts
+			}
+			counts[bucket]++
+			if name != "" {
+				t.names[key] = name
+			}
+		}
+	}
+
+	t.prune(cutoff)
+}
+
+// newsTrendKeys returns the keys a news item is counted under, with their display names
+func newsTrendKeys(news NewsV2) map[newsTrendKey]string {
+	keys := make(map[newsTrendKey]string)
+	for _, ticker := range news.Tickers {
+		if ticker.Symbol != "" {
+			keys[newsTrendKey{NewsTrendTicker, strings.ToUpper(ticker.Symbol)}] = ticker.Name
+		}
+	}
+	if news.Sectors != nil && news.Sectors.ID != "" {
+		keys[newsTrendKey{NewsTrendSector, news.Sectors.ID}] = news.Sectors.Name
+	}
+	if news.Categories != nil && news.Categories.ID != "" {
+		keys[newsTrendKey{NewsTrendCategory, news.Categories.ID}] = news.Categories.Name
+	}
+	return keys
+}
+
+// Consume counts every news item of a subscribed stream until ctx ends or the stream fails,
+// see NewsStream.Run.
+func (t *NewsTrends) Consume(ctx context.Context, stream *NewsStream) error {
+	return stream.Run(ctx, func(news NewsV2, err error) error {
+		if err == nil {
+			t.Add(news)
+		}
+		return nil
+	})
+}
+
+// Backfill counts the news matching params published within the retention, paging through
+// GetNewsV2 oldest first. The ordering and TimestampFrom of params are overridden.
+func (t *NewsTrends) Backfill(ctx context.Context, client *Client, params GetNewsParams) error {
+	from := t.now().Add(-t.opts.Retention)
+	size := newsBackfillPageSize
+	if params.Size != nil {
+		size = *params.Size
+	}
+
+	params.Size = &size
+	params.Page = nil
+	params.OrderBy = NewsOrderByTimestamp
+	params.OrderByDirection = SortDirectionAsc
+
+	for page := 0; page < newsTrendsMaxPages; page++ {
+		params.TimestampFrom = from.UTC().Format(time.RFC3339)
+
+		resp, err := client.GetNewsV2(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to backfill news trends: %w", err)
+		}
+		t.Add(resp.Items...)
+
+		if len(resp.Items) < size {
+			return nil
+		}
+		last := resp.Items[len(resp.Items)-1].Timestamp
+		if !last.After(from) {
+			return nil
+		}
+		from = last
+	}
+
+	return nil
+}
+
+// Trending ranks the keys of kind by the z-score of their mentions in the window ending now
+// against the preceding windows of the same length within the retention. Keys with fewer than
+// MinMentions mentions in the window are left out; limit caps the list unless it is 0.
+func (t *NewsTrends) Trending(kind NewsTrendKind, window time.Duration, limit int) ([]NewsTrend, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	width := int64(window / t.opts.Resolution)
+	slots := int64((t.opts.Retention - window) / window)
+	if width < 1 || slots < 2 {
+		return nil, fmt.Errorf("window %s must span at least one resolution step and at most a third of the retention", window)
+	}
+
+	current := t.bucket(t.now())
+	var trends []NewsTrend
+	for key, counts := range t.counts {
+		if key.kind != kind {
+			continue
+		}
+
+		// Slot 0 is the window itself, the following ones the baseline windows before it
+		windows := make([]float64, slots+1)
+		for bucket, count := range counts {
+			if slot := (current - bucket) / width; bucket <= current && slot <= slots {
+				windows[slot] += float64(count)
+			}
+		}
+
+		mentions := int(windows[0])
+		if mentions < t.opts.MinMentions {
+			continue
+		}
+		mean, stdDev := meanStdDev(windows[1:])
+
+		trends = append(trends, NewsTrend{
+			Kind:           kind,
+			Key:            key.key,
+			Name:           t.names[key],
+			Mentions:       mentions,
+			BaselineMean:   mean,
+			BaselineStdDev: stdDev,
+			ZScore:         (float64(mentions) - mean) / max(stdDev, 1),
+		})
+	}
+
+	slices.SortFunc(trends, func(a, b NewsTrend) int {
+		switch {
+		case a.ZScore != b.ZScore:
+			if a.ZScore > b.ZScore {
+				return -1
+			}
+			return 1
+		case a.Mentions != b.Mentions:
+			return b.Mentions - a.Mentions
+		default:
+			return strings.Compare(a.Key, b.Key)
+		}
+	})
+
+	if limit > 0 && len(trends) > limit {
+		trends = trends[:limit]
+	}
+
+	return trends, nil
+}
+
+// Mentions returns the number of mentions of a key in the window ending now.
+func (t *NewsTrends) Mentions(kind NewsTrendKind, key string, window time.Duration) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if kind == NewsTrendTicker {
+		key = strings.ToUpper(key)
+	}
+	current := t.bucket(t.now())
+	width := max(int64(window/t.opts.Resolution), 1)
+
+	total := 0
+	for bucket, count := range t.counts[newsTrendKey{kind, key}] {
+		if bucket > current-width && bucket <= current {
+			total += count
+		}
+	}
+	return total
+}
+
+func (t *NewsTrends) bucket(at time.Time) int64 {
+	return at.UnixNano() / int64(t.opts.Resolution)
+}
+
+// prune drops the counts and seen IDs that fell out of the retention, at most once per
+// resolution step
+func (t *NewsTrends) prune(cutoff time.Time) {
+	oldest := t.bucket(cutoff)
+	if oldest <= t.pruned {
+		return
+	}
+	t.pruned = oldest
+
+	for key, counts := range t.counts {
+		for bucket := range counts {
+			if bucket < oldest {
+				delete(counts, bucket)
+			}
+		}
+		if len(counts) == 0 {
+			delete(t.counts, key)
+			delete(t.names, key)
+		}
+	}
+
+	for id, timestamp := range t.seen {
+		if timestamp.Before(cutoff) {
+			delete(t.seen, id)
+		}
+	}
+}
+
+func meanStdDev(values []float64) (float64, float64) {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
@@ -0,0 +1,97 @@
+package laplace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewsTrends(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	trends := NewNewsTrends(NewsTrendOptions{Resolution: time.Minute, Retention: 6 * time.Hour, MinMentions: 2})
+	trends.now = func() time.Time { return now }
+
+	id := 0
+	mention := func(at time.Time, symbol string) NewsV2 {
+		id++
+		return NewsV2{
+			ID:         fmt.Sprint(id),
+			Timestamp:  at,
+			Tickers:    []NewsTicker{{Symbol: symbol, Name: symbol + " Inc"}},
+			Categories: &NewsCategories{ID: "earnings", Name: "Earnings"},
+		}
+	}
+
+	// AAPL gets two mentions every hour; MSFT is quiet until a burst in the last hour
+	var news []NewsV2
+	for hour := 1; hour <= 5; hour++ {
+		at := now.Add(-time.Duration(hour)*time.Hour - 10*time.Minute)
+		news = append(news, mention(at, "AAPL"), mention(at.Add(time.Minute), "AAPL"))
+	}
+	news = append(news, mention(now.Add(-10*time.Minute), "AAPL"), mention(now.Add(-9*time.Minute), "AAPL"))
+	for i := 0; i < 6; i++ {
+		news = append(news, mention(now.Add(-time.Duration(i)*time.Minute), "msft"))
+	}
+	news = append(news, mention(now.Add(-7*time.Hour), "TSLA"))
+
+	trends.Add(news...)
+	trends.Add(news[len(news)-2]) // counted once
+
+	list, err := trends.Trending(NewsTrendTicker, time.Hour, 0)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	require.Equal(t, "MSFT", list[0].Key)
+	require.Equal(t, "msft Inc", list[0].Name)
+	require.Equal(t, 6, list[0].Mentions)
+	require.Equal(t, 6.0, list[0].ZScore)
+
+	require.Equal(t, "AAPL", list[1].Key)
+	require.Equal(t, 2, list[1].Mentions)
+	require.Equal(t, 2.0, list[1].BaselineMean)
+	require.Equal(t, 0.0, list[1].ZScore)
+
+	categories, err := trends.Trending(NewsTrendCategory, time.Hour, 1)
+	require.NoError(t, err)
+	require.Len(t, categories, 1)
+	require.Equal(t, "Earnings", categories[0].Name)
+	require.Equal(t, 8, categories[0].Mentions)
+
+	require.Equal(t, 6, trends.Mentions(NewsTrendTicker, "msft", time.Hour))
+	require.Zero(t, trends.Mentions(NewsTrendTicker, "TSLA", 6*time.Hour))
+
+	_, err = trends.Trending(NewsTrendTicker, 3*time.Hour, 0)
+	require.Error(t, err)
+}
+
+func TestNewsTrendsBackfill(t *testing.T) {
+	now := time.Now()
+	var froms []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		froms = append(froms, r.URL.Query().Get("timestampFrom"))
+		items := []NewsV2{
+			{ID: "1", Timestamp: now.Add(-2 * time.Hour), Tickers: []NewsTicker{{Symbol: "AAPL"}}},
+			{ID: "2", Timestamp: now.Add(-time.Hour), Tickers: []NewsTicker{{Symbol: "AAPL"}}},
+		}
+		if len(froms) > 1 {
+			items = items[1:]
+		}
+		json.NewEncoder(w).Encode(PaginatedResponse[NewsV2]{Items: items})
+	}))
+	defer srv.Close()
+
+	trends := NewNewsTrends(NewsTrendOptions{Retention: 24 * time.Hour})
+	size := 2
+	err := trends.Backfill(context.Background(), newOfflineTestClient(t, srv.URL), GetNewsParams{Region: RegionUs, Locale: LocaleEn, Size: &size})
+	require.NoError(t, err)
+
+	require.Len(t, froms, 2)
+	require.Equal(t, now.Add(-time.Hour).UTC().Format(time.RFC3339), froms[1])
+	require.Equal(t, 2, trends.Mentions(NewsTrendTicker, "AAPL", 3*time.Hour))
+}
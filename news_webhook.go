@@ -0,0 +1,273 @@
+package laplace
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Headers sent with every news webhook delivery
+const (
+	NewsWebhookSignatureHeader = "X-Laplace-Signature"
+	NewsWebhookTimestampHeader = "X-Laplace-Timestamp"
+	NewsWebhookDeliveryHeader  = "X-Laplace-Delivery"
+)
+
+// NewsWebhookOptions configures a NewsWebhookForwarder.
+type NewsWebhookOptions struct {
+	// URLs are the webhooks every news item is posted to
+	URLs []string
+	// Secret is the HMAC-SHA256 key deliveries are signed with; an empty secret sends them unsigned
+	Secret string
+	// MaxAttempts is the number of delivery attempts per webhook before a news item is
+	// dead-lettered, 5 by default
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubled up to MaxBackoff on every
+	// following one; 1 second and 1 minute by default
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Timeout bounds a single delivery attempt, 10 seconds by default
+	Timeout time.Duration
+	// DeadLetterPath is the JSON Lines file failed deliveries are appended to. Without it they
+	// are only logged.
+	DeadLetterPath string
+	// QueueSize is the number of news items buffered per webhook, 256 by default. News items
+	// arriving while a webhook's queue is full are dead-lettered for that webhook.
+	QueueSize int
+	// HTTPClient sends the deliveries, http.DefaultClient by default
+	HTTPClient *http.Client
+}
+
+// errNewsWebhookQueueFull is the dead-letter error of news items dropped by a backed up webhook
+var errNewsWebhookQueueFull = errors.New("webhook queue is full")
+
+// NewsWebhookDeadLetter is a dead-letter file entry: a news item that could not be delivered.
+type NewsWebhookDeadLetter struct {
+	URL  string `json:"url"`
+	News NewsV2 `json:"news"`
+	// Attempts is 0 for news items that were dropped before any delivery was attempted
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// NewsWebhookForwarder posts every news item of a news stream to a set of webhooks. Each
+// webhook has its own queue, so a slow or failing webhook does not hold up the others.
+type NewsWebhookForwarder struct {
+	c      *Client
+	params StreamNewsParams
+	opts   NewsWebhookOptions
+
+	deadLetterMu sync.Mutex
+}
+
+// NewNewsWebhookForwarder creates a forwarder for the news stream with the given parameters.
+// Call Run to start forwarding.
+func (c *Client) NewNewsWebhookForwarder(params StreamNewsParams, opts NewsWebhookOptions) *NewsWebhookForwarder {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Minute
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 256
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+
+	return &NewsWebhookForwarder{c: c, params: params, opts: opts}
+}
+
+// Run subscribes to the news stream and forwards news until ctx ends or the stream fails with
+// a terminal error, which is returned. Queued deliveries are finished before Run returns;
+// once ctx has ended, queued and in-flight news items are dead-lettered instead.
+func (f *NewsWebhookForwarder) Run(ctx context.Context) error {
+	if len(f.opts.URLs) == 0 {
+		return fmt.Errorf("no webhook URLs configured")
+	}
+
+	stream, err := f.c.CreateNewsStream(ctx, f.params)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	var wg sync.WaitGroup
+	queues := make([]chan NewsV2, len(f.opts.URLs))
+	for i, url := range f.opts.URLs {
+		queues[i] = make(chan NewsV2, f.opts.QueueSize)
+		wg.Add(1)
+		go func(url string, queue <-chan NewsV2) {
+			defer wg.Done()
+			for news := range queue {
+				if ctx.Err() != nil {
+					f.deadLetter(url, news, 0, ctx.Err())
+					continue
+				}
+				f.deliver(ctx, url, news)
+			}
+		}(url, queues[i])
+	}
+
+	err = stream.Run(ctx, func(news NewsV2, err error) error {
+		if err != nil {
+			f.c.logger.Warnf("skipping news event: %v", err)
+			return nil
+		}
+
+		// Never block on a queue, which would hold up every webhook behind a failing one
+		for i, queue := range queues {
+			select {
+			case queue <- news:
+			default:
+				f.deadLetter(f.opts.URLs[i], news, 0, errNewsWebhookQueueFull)
+			}
+		}
+		return nil
+	})
+
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+
+	return err
+}
+
+// deliver posts news to url, retrying with backoff, and dead-letters it once every attempt
+// failed, the webhook rejected it or ctx ended
+func (f *NewsWebhookForwarder) deliver(ctx context.Context, url string, news NewsV2) {
+	body, err := json.Marshal(news)
+	if err != nil {
+		f.c.logger.Errorf("failed to encode news %s: %v", news.ID, err)
+		return
+	}
+
+	backoff := f.opts.InitialBackoff
+	attempt := 1
+	for ; ; attempt++ {
+		retry, err := f.post(ctx, url, news.ID, body)
+		if err == nil {
+			return
+		}
+		if !retry || attempt == f.opts.MaxAttempts || ctx.Err() != nil {
+			f.deadLetter(url, news, attempt, err)
+			return
+		}
+
+		f.c.logger.Warnf("news webhook delivery to %s failed, retrying in %s: %v", url, backoff, err)
+		select {
+		case <-ctx.Done():
+			f.deadLetter(url, news, attempt, err)
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, f.opts.MaxBackoff)
+	}
+}
+
+// post makes a single delivery attempt and reports whether a failure is worth retrying
+func (f *NewsWebhookForwarder) post(ctx context.Context, url, id string, body []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(NewsWebhookTimestampHeader, timestamp)
+	req.Header.Set(NewsWebhookDeliveryHeader, id)
+	if f.opts.Secret != "" {
+		req.Header.Set(NewsWebhookSignatureHeader, SignNewsWebhook(f.opts.Secret, timestamp, body))
+	}
+
+	resp, err := f.opts.HTTPClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	// Client errors will not go away by retrying, except for timeouts and rate limiting
+	retry := resp.StatusCode >= http.StatusInternalServerError ||
+		resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+}
+
+// deadLetter records a delivery that could not be made
+func (f *NewsWebhookForwarder) deadLetter(url string, news NewsV2, attempts int, deliveryErr error) {
+	f.c.logger.Errorf("giving up news webhook delivery of %s to %s after %d attempts: %v", news.ID, url, attempts, deliveryErr)
+
+	if f.opts.DeadLetterPath == "" {
+		return
+	}
+
+	line, err := json.Marshal(NewsWebhookDeadLetter{
+		URL:      url,
+		News:     news,
+		Attempts: attempts,
+		Error:    deliveryErr.Error(),
+		FailedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		f.c.logger.Errorf("failed to encode dead letter: %v", err)
+		return
+	}
+
+	f.deadLetterMu.Lock()
+	defer f.deadLetterMu.Unlock()
+
+	file, err := os.OpenFile(f.opts.DeadLetterPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		f.c.logger.Errorf("failed to open dead letter file: %v", err)
+		return
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		f.c.logger.Errorf("failed to write dead letter: %v", err)
+	}
+}
+
+// SignNewsWebhook returns the signature header value of a delivery: the hex-encoded
+// HMAC-SHA256 of the timestamp header value, a dot and the body, prefixed with "sha256=".
+func SignNewsWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyNewsWebhook reports whether signature is a valid signature of a delivery, for use by
+// webhook receivers. Receivers should also reject timestamps too far in the past.
+func VerifyNewsWebhook(secret, timestamp, signature string, body []byte) bool {
+	return hmac.Equal([]byte(signature), []byte(SignNewsWebhook(secret, timestamp, body)))
+}
@@ -0,0 +1,216 @@
+package laplace
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignNewsWebhook(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	signature := SignNewsWebhook("secret", "1740000000", body)
+
+	require.True(t, VerifyNewsWebhook("secret", "1740000000", signature, body))
+	require.False(t, VerifyNewsWebhook("other", "1740000000", signature, body))
+	require.False(t, VerifyNewsWebhook("secret", "1740000001", signature, body))
+	require.False(t, VerifyNewsWebhook("secret", "1740000000", signature, []byte(`{"id":"2"}`)))
+}
+
+func TestNewsWebhookForwarder(t *testing.T) {
+	stream := newFiniteSSEServer(t, `[{"id":"1"},{"id":"2"}]`)
+
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	var delivered []string
+
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		require.True(t, VerifyNewsWebhook("secret", r.Header.Get(NewsWebhookTimestampHeader), r.Header.Get(NewsWebhookSignatureHeader), body))
+
+		id := r.Header.Get(NewsWebhookDeliveryHeader)
+		mu.Lock()
+		defer mu.Unlock()
+
+		attempts[id]++
+		if attempts[id] < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer flaky.Close()
+
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer rejecting.Close()
+
+	deadLetterPath := filepath.Join(t.TempDir(), "dead-letter.jsonl")
+	client := newOfflineTestClient(t, stream.URL)
+	forwarder := client.NewNewsWebhookForwarder(StreamNewsParams{Region: RegionUs, Locale: LocaleEn}, NewsWebhookOptions{
+		URLs:           []string{flaky.URL, rejecting.URL},
+		Secret:         "secret",
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		DeadLetterPath: deadLetterPath,
+		// Deliveries count once their response reached the forwarder, as cancelling ctx
+		// before that dead-letters them
+		HTTPClient: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			resp, err := http.DefaultTransport.RoundTrip(r)
+			if err == nil && resp.StatusCode == http.StatusOK {
+				mu.Lock()
+				delivered = append(delivered, r.Header.Get(NewsWebhookDeliveryHeader))
+				mu.Unlock()
+			}
+			return resp, err
+		})},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The test server ends the stream after two items; the forwarder keeps reconnecting to it
+	// until ctx ends, so stop once both webhooks are done
+	go func() {
+		for {
+			mu.Lock()
+			done := len(delivered) == 2
+			mu.Unlock()
+
+			if done && countLines(t, deadLetterPath) == 2 {
+				cancel()
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	err := forwarder.Run(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+
+	require.ElementsMatch(t, []string{"1", "2"}, delivered)
+
+	file, err := os.Open(deadLetterPath)
+	require.NoError(t, err)
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var letter NewsWebhookDeadLetter
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &letter))
+		require.Equal(t, rejecting.URL, letter.URL)
+		require.Equal(t, 1, letter.Attempts)
+		require.Contains(t, []string{"1", "2"}, letter.News.ID)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	require.NoError(t, err)
+
+	lines := 0
+	for _, b := range data {
+		if b == '\n' {
+			lines++
+		}
+	}
+	return lines
+}
+
+func TestNewsWebhookForwarderStuckWebhook(t *testing.T) {
+	// News items arrive apart, giving the webhooks time to take them off their queues
+	stream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		for _, id := range []string{"1", "2", "3"} {
+			fmt.Fprintf(w, "data:[{\"id\":%q}]\n\n", id)
+			w.(http.Flusher).Flush()
+			time.Sleep(50 * time.Millisecond)
+		}
+		<-r.Context().Done()
+	}))
+	defer stream.Close()
+
+	var mu sync.Mutex
+	var delivered []string
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		delivered = append(delivered, r.Header.Get(NewsWebhookDeliveryHeader))
+	}))
+	defer fast.Close()
+
+	// The stuck webhook never answers: it holds the first item, queues the second and drops
+	// the third
+	stuck := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	defer stuck.Close()
+
+	deadLetterPath := filepath.Join(t.TempDir(), "dead-letter.jsonl")
+	client := newOfflineTestClient(t, stream.URL)
+	forwarder := client.NewNewsWebhookForwarder(StreamNewsParams{Region: RegionUs, Locale: LocaleEn}, NewsWebhookOptions{
+		URLs:           []string{stuck.URL, fast.URL},
+		Timeout:        time.Minute,
+		QueueSize:      1,
+		DeadLetterPath: deadLetterPath,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The fast webhook gets every item while the stuck one is still on its first; ctx then ends
+	// with the stuck webhook's items in flight or queued
+	go func() {
+		for {
+			mu.Lock()
+			done := len(delivered) == 3
+			mu.Unlock()
+
+			if done && countLines(t, deadLetterPath) >= 1 {
+				cancel()
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	err := forwarder.Run(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.ElementsMatch(t, []string{"1", "2", "3"}, delivered)
+
+	// Nothing is lost: the overflowing, queued and in-flight items are all dead-lettered
+	data, err := os.ReadFile(deadLetterPath)
+	require.NoError(t, err)
+
+	var ids []string
+	for _, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+		var letter NewsWebhookDeadLetter
+		require.NoError(t, json.Unmarshal(line, &letter))
+		require.Equal(t, stuck.URL, letter.URL)
+		ids = append(ids, letter.News.ID)
+	}
+	require.ElementsMatch(t, []string{"1", "2", "3"}, ids)
+}
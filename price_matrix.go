@@ -0,0 +1,163 @@
+package laplace
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// PriceMatrix holds the prices of several symbols on a common timestamp index: Values[i][j] is
+// the price of Symbols[j] at Times[i], NaN where it is missing.
+type PriceMatrix struct {
+	Times   []time.Time
+	Symbols []string
+	Values  [][]float64
+}
+
+// NewPriceMatrix aligns the close prices of several symbols as AlignSeries does, with symbols
+// in alphabetical order. Times are the midnights of the calendar dates in location, or the
+// times of the bars with a nil location. Series must be ordered by date.
+func NewPriceMatrix(series map[string][]PriceDataPoint, fill AlignFill, location *time.Location) (*PriceMatrix, error) {
+	symbols := make([]string, 0, len(series))
+	for symbol := range series {
+		symbols = append(symbols, symbol)
+	}
+	slices.Sort(symbols)
+
+	columns := make([][]PriceDataPoint, len(symbols))
+	for i, symbol := range symbols {
+		columns[i] = series[symbol]
+	}
+	return newPriceMatrix(symbols, columns, fill, location)
+}
+
+// PriceMatrixFromGraphs aligns one period of the graphs returned by GetHistoricalPrices, with
+// symbols in the order of graphs.
+func PriceMatrixFromGraphs(graphs []StockPriceGraph, period HistoricalPricePeriod, fill AlignFill, location *time.Location) (*PriceMatrix, error) {
+	symbols := make([]string, len(graphs))
+	columns := make([][]PriceDataPoint, len(graphs))
+	for i, graph := range graphs {
+		points, ok := graph.Period(period)
+		if !ok {
+			return nil, fmt.Errorf("price graphs have no period %q", period)
+		}
+		symbols[i], columns[i] = graph.Symbol, points
+	}
+	return newPriceMatrix(symbols, columns, fill, location)
+}
+
+func newPriceMatrix(symbols []string, columns [][]PriceDataPoint, fill AlignFill, location *time.Location) (*PriceMatrix, error) {
+	index, rows, err := alignIndex(symbols, columns, location, fill)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := &PriceMatrix{
+		Times:   index,
+		Symbols: symbols,
+		Values:  make([][]float64, len(index)),
+	}
+	for i := range index {
+		matrix.Values[i] = make([]float64, len(symbols))
+		for j := range symbols {
+			switch {
+			case rows[j][i] >= 0:
+				matrix.Values[i][j] = columns[j][rows[j][i]].Close
+			case i > 0 && fill == AlignFillForward:
+				matrix.Values[i][j] = matrix.Values[i-1][j]
+			default:
+				matrix.Values[i][j] = math.NaN()
+			}
+		}
+	}
+
+	return matrix, nil
+}
+
+// Column returns the prices of a symbol, or false if the matrix does not hold it.
+func (m *PriceMatrix) Column(symbol string) ([]float64, bool) {
+	j := slices.Index(m.Symbols, symbol)
+	if j < 0 {
+		return nil, false
+	}
+	column := make([]float64, len(m.Times))
+	for i, row := range m.Values {
+		column[i] = row[j]
+	}
+	return column, true
+}
+
+// Returns returns the matrix of simple returns from each timestamp to the next, dated by the
+// later one. A return is NaN where either price is missing.
+func (m *PriceMatrix) Returns() *PriceMatrix {
+	returns := &PriceMatrix{Symbols: slices.Clone(m.Symbols)}
+	for i := 1; i < len(m.Times); i++ {
+		row := make([]float64, len(m.Symbols))
+		for j := range row {
+			// NaN prices propagate to the return
+			row[j] = m.Values[i][j]/m.Values[i-1][j] - 1
+		}
+		returns.Times = append(returns.Times, m.Times[i])
+		returns.Values = append(returns.Values, row)
+	}
+	return returns
+}
+
+// Normalize returns the matrix with every column rebased to 100 at its first price, so that
+// symbols at different price levels can be compared.
+func (m *PriceMatrix) Normalize() *PriceMatrix {
+	normalized := &PriceMatrix{
+		Times:   slices.Clone(m.Times),
+		Symbols: slices.Clone(m.Symbols),
+		Values:  make([][]float64, len(m.Values)),
+	}
+	bases := make([]float64, len(m.Symbols))
+	for j := range bases {
+		bases[j] = math.NaN()
+	}
+
+	for i, row := range m.Values {
+		normalized.Values[i] = make([]float64, len(row))
+		for j, value := range row {
+			if math.IsNaN(bases[j]) && !math.IsNaN(value) {
+				bases[j] = value
+			}
+			normalized.Values[i][j] = 100 * value / bases[j]
+		}
+	}
+	return normalized
+}
+
+// WriteCSV writes the matrix as CSV: a header of "time" and the symbols, then a row per
+// timestamp in RFC 3339 in location (UTC if nil). Missing prices are left empty.
+func (m *PriceMatrix) WriteCSV(w io.Writer, location *time.Location) error {
+	if location == nil {
+		location = time.UTC
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append([]string{"time"}, m.Symbols...)); err != nil {
+		return err
+	}
+
+	record := make([]string, len(m.Symbols)+1)
+	for i, row := range m.Values {
+		record[0] = m.Times[i].In(location).Format(time.RFC3339)
+		for j, value := range row {
+			record[j+1] = ""
+			if !math.IsNaN(value) && !math.IsInf(value, 0) {
+				record[j+1] = strconv.FormatFloat(value, 'f', -1, 64)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
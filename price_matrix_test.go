@@ -0,0 +1,160 @@
+package laplace
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockPriceGraphPeriod(t *testing.T) {
+	graph := StockPriceGraph{OneWeek: []PriceDataPoint{{Date: 1}}, FiveYear: []PriceDataPoint{{Date: 2}}}
+
+	points, ok := graph.Period(HistoricalPricePeriodOneWeek)
+	require.True(t, ok)
+	require.Equal(t, graph.OneWeek, points)
+
+	points, ok = graph.Period(HistoricalPricePeriodFiveYear)
+	require.True(t, ok)
+	require.Equal(t, graph.FiveYear, points)
+
+	_, ok = graph.Period(HistoricalPricePeriodAll)
+	require.False(t, ok)
+}
+
+func matrixTestSeries() map[string][]PriceDataPoint {
+	return map[string][]PriceDataPoint{
+		// Dated in seconds, the other in milliseconds
+		"THYAO": {{Date: 1_700_000_000, Close: 10}, {Date: 1_700_086_400, Close: 11}, {Date: 1_700_259_200, Close: 12.1}},
+		"ASELS": {{Date: 1_700_086_400_000, Close: 50}, {Date: 1_700_172_800_000, Close: 55}, {Date: 1_700_259_200_000, Close: 44}},
+	}
+}
+
+func requireRow(t *testing.T, want, got []float64) {
+	t.Helper()
+	require.Len(t, got, len(want))
+	for i := range want {
+		if math.IsNaN(want[i]) {
+			require.True(t, math.IsNaN(got[i]), "column %d: %v is not NaN", i, got[i])
+			continue
+		}
+		require.InDelta(t, want[i], got[i], 1e-9, "column %d", i)
+	}
+}
+
+func TestPriceMatrixFill(t *testing.T) {
+	nan := math.NaN()
+
+	matrix, err := NewPriceMatrix(matrixTestSeries(), AlignFillNone, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"ASELS", "THYAO"}, matrix.Symbols)
+	require.Len(t, matrix.Times, 4)
+	require.Equal(t, time.Unix(1_700_000_000, 0).UTC(), matrix.Times[0])
+	requireRow(t, []float64{nan, 10}, matrix.Values[0])
+	requireRow(t, []float64{55, nan}, matrix.Values[2])
+
+	matrix, err = NewPriceMatrix(matrixTestSeries(), AlignFillForward, nil)
+	require.NoError(t, err)
+	requireRow(t, []float64{nan, 10}, matrix.Values[0])
+	requireRow(t, []float64{55, 11}, matrix.Values[2])
+
+	matrix, err = NewPriceMatrix(matrixTestSeries(), AlignFillDrop, nil)
+	require.NoError(t, err)
+	require.Len(t, matrix.Times, 2)
+	requireRow(t, []float64{50, 11}, matrix.Values[0])
+	requireRow(t, []float64{44, 12.1}, matrix.Values[1])
+
+	column, ok := matrix.Column("THYAO")
+	require.True(t, ok)
+	require.Equal(t, []float64{11, 12.1}, column)
+	_, ok = matrix.Column("GARAN")
+	require.False(t, ok)
+
+	_, err = NewPriceMatrix(matrixTestSeries(), "backward", nil)
+	require.ErrorContains(t, err, "invalid align fill")
+
+	_, err = NewPriceMatrix(map[string][]PriceDataPoint{"THYAO": {{Date: 2}, {Date: 1}}}, AlignFillNone, nil)
+	require.ErrorContains(t, err, "not ordered")
+}
+
+func TestPriceMatrixCalendarDates(t *testing.T) {
+	istanbul := RegionTr.Location()
+	series := map[string][]PriceDataPoint{
+		// Daily bars at midnight in Istanbul and at the open in New York
+		"THYAO": {{Date: time.Date(2025, 3, 5, 0, 0, 0, 0, istanbul).UnixMilli(), Close: 10}, {Date: time.Date(2025, 3, 6, 0, 0, 0, 0, istanbul).UnixMilli(), Close: 11}},
+		"AAPL":  {{Date: time.Date(2025, 3, 6, 9, 30, 0, 0, RegionUs.Location()).UnixMilli(), Close: 20}},
+	}
+
+	matrix, err := NewPriceMatrix(series, AlignFillDrop, istanbul)
+	require.NoError(t, err)
+	require.Len(t, matrix.Times, 1)
+	require.Equal(t, "2025-03-06T00:00:00+03:00", matrix.Times[0].Format(time.RFC3339))
+	requireRow(t, []float64{20, 11}, matrix.Values[0])
+
+	// The matrix and AlignSeries agree on dates and fills
+	aligned, err := AlignSeries(series, AlignFillForward, istanbul)
+	require.NoError(t, err)
+	matrix, err = NewPriceMatrix(series, AlignFillForward, istanbul)
+	require.NoError(t, err)
+	require.Equal(t, aligned.Dates, matrix.Times)
+	for j, symbol := range matrix.Symbols {
+		column, _ := matrix.Column(symbol)
+		for i, point := range aligned.Series[symbol] {
+			if point == (PriceDataPoint{Date: point.Date}) {
+				require.True(t, math.IsNaN(column[i]), "%s at %d", matrix.Symbols[j], i)
+				continue
+			}
+			require.Equal(t, point.Close, column[i])
+		}
+	}
+}
+
+func TestPriceMatrixFromGraphs(t *testing.T) {
+	series := matrixTestSeries()
+	graphs := []StockPriceGraph{
+		{Symbol: "THYAO", OneMonth: series["THYAO"]},
+		{Symbol: "ASELS", OneMonth: series["ASELS"]},
+	}
+
+	matrix, err := PriceMatrixFromGraphs(graphs, HistoricalPricePeriodOneMonth, AlignFillDrop, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"THYAO", "ASELS"}, matrix.Symbols)
+	requireRow(t, []float64{11, 50}, matrix.Values[0])
+
+	_, err = PriceMatrixFromGraphs(graphs, HistoricalPricePeriodSixMonth, AlignFillDrop, nil)
+	require.ErrorContains(t, err, "no period")
+}
+
+func TestPriceMatrixColumnOps(t *testing.T) {
+	nan := math.NaN()
+	matrix, err := NewPriceMatrix(matrixTestSeries(), AlignFillForward, nil)
+	require.NoError(t, err)
+
+	returns := matrix.Returns()
+	require.Equal(t, matrix.Times[1:], returns.Times)
+	requireRow(t, []float64{nan, 0.1}, returns.Values[0])
+	requireRow(t, []float64{0.1, 0}, returns.Values[1])
+	requireRow(t, []float64{-0.2, 0.1}, returns.Values[2])
+
+	normalized := matrix.Normalize()
+	requireRow(t, []float64{nan, 100}, normalized.Values[0])
+	requireRow(t, []float64{100, 110}, normalized.Values[1])
+	requireRow(t, []float64{88, 121}, normalized.Values[3])
+	// The source matrix is left as it was
+	requireRow(t, []float64{44, 12.1}, matrix.Values[3])
+}
+
+func TestPriceMatrixWriteCSV(t *testing.T) {
+	matrix, err := NewPriceMatrix(matrixTestSeries(), AlignFillNone, nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, matrix.WriteCSV(&buf, RegionTr.Location()))
+	require.Equal(t, "time,ASELS,THYAO\n"+
+		"2023-11-15T01:13:20+03:00,,10\n"+
+		"2023-11-16T01:13:20+03:00,50,11\n"+
+		"2023-11-17T01:13:20+03:00,55,\n"+
+		"2023-11-18T01:13:20+03:00,44,12.1\n", buf.String())
+}
@@ -0,0 +1,229 @@
+package laplace
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// defaultPriceRangeChunkBars is the number of interval lengths a chunk spans by default. Bars
+// only exist during sessions, so a chunk holds far fewer bars than that.
+const defaultPriceRangeChunkBars = 7200
+
+// PriceRangeOptions configures GetCustomHistoricalPricesRange.
+type PriceRangeOptions struct {
+	// ChunkSpan is the time range fetched per request, 7200 interval lengths by default: 5 days
+	// of 1 minute bars or 25 days of 5 minute bars
+	ChunkSpan time.Duration
+	// Concurrency is the number of chunks fetched in parallel, 4 by default
+	Concurrency int
+	// RequestsPerSecond caps the rate chunks are requested at, 5 by default
+	RequestsPerSecond float64
+	// GapThreshold is the trading time between consecutive bars above which they are reported
+	// as a gap. Only time within the trading stretches of Session counts, so nights, weekends
+	// and holidays are never gaps, while a missing trading day or a session cut short is. It is
+	// one and a half times the trading time a bar covers by default: the interval for intraday
+	// bars, a session for daily ones and five sessions a week beyond.
+	GapThreshold time.Duration
+	// Session is the trading hours gaps are measured in, DefaultMarketSession of the region by
+	// default. Add holidays to it to keep them from being reported.
+	Session *MarketSession
+	// Detail is passed on to GetCustomHistoricalPrices
+	Detail bool
+}
+
+// PriceGap is a stretch of a range without bars, between the bars at From and To (or the
+// bounds of the range).
+type PriceGap struct {
+	From time.Time
+	To   time.Time
+}
+
+// PriceRange is a stitched price series.
+type PriceRange struct {
+	// Points are ordered by date, without duplicates
+	Points []PriceDataPoint
+	// Chunks is the number of requests made
+	Chunks int
+	// Duplicates is the number of bars returned by more than one chunk and dropped
+	Duplicates int
+	// Unordered is the number of chunks that were not returned in date order and were sorted
+	Unordered int
+	// Gaps lists the stretches without bars longer than the gap threshold
+	Gaps []PriceGap
+}
+
+// GetCustomHistoricalPricesRange fetches a long [from, to] range of bars by splitting it into
+// chunks, fetching them concurrently at a limited rate through
+// GetCustomHistoricalPricesBetween and stitching the results into one ordered series. Bars at
+// chunk boundaries are deduplicated, and gaps are reported rather than treated as errors. The
+// first failing chunk fails the whole range.
+func (c *Client) GetCustomHistoricalPricesRange(ctx context.Context, symbol string, region Region, from, to time.Time, interval HistoricalPriceInterval, opts PriceRangeOptions) (*PriceRange, error) {
+	if from.After(to) {
+		return nil, fmt.Errorf("invalid time range, %s is after %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	step := interval.Duration()
+	if step == 0 {
+		return nil, fmt.Errorf("unsupported interval %q", interval)
+	}
+
+	if opts.ChunkSpan <= 0 {
+		opts.ChunkSpan = step * defaultPriceRangeChunkBars
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	if opts.RequestsPerSecond <= 0 {
+		opts.RequestsPerSecond = 5
+	}
+	if opts.Session == nil {
+		session := DefaultMarketSession(region)
+		opts.Session = &session
+	}
+	if opts.GapThreshold <= 0 {
+		opts.GapThreshold = barTradingTime(step, *opts.Session) * 3 / 2
+	}
+
+	var chunks []priceChunk
+	for start := from; ; start = start.Add(opts.ChunkSpan) {
+		end := start.Add(opts.ChunkSpan)
+		if !end.Before(to) {
+			chunks = append(chunks, priceChunk{start, to})
+			break
+		}
+		chunks = append(chunks, priceChunk{start, end})
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	limiter := time.NewTicker(time.Duration(float64(time.Second) / opts.RequestsPerSecond))
+	defer limiter.Stop()
+
+	results := make([][]PriceDataPoint, len(chunks))
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	sem := make(chan struct{}, opts.Concurrency)
+	for i, chunk := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		select {
+		case <-limiter.C:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, chunk priceChunk) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			points, err := c.GetCustomHistoricalPricesBetween(ctx, symbol, region, chunk.from, chunk.to, interval, opts.Detail)
+			if err != nil {
+				fail(fmt.Errorf("failed to get %s prices from %s to %s: %w", symbol, chunk.from.Format(time.RFC3339), chunk.to.Format(time.RFC3339), err))
+				return
+			}
+			results[i] = points
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	stitched := stitchPriceChunks(results)
+	stitched.Chunks = len(chunks)
+	stitched.Gaps = findPriceGaps(stitched.Points, from, to, opts.GapThreshold, *opts.Session)
+	return stitched, nil
+}
+
+// priceChunk is the time range of one request of a chunked fetch
+type priceChunk struct {
+	from time.Time
+	to   time.Time
+}
+
+// stitchPriceChunks concatenates chunks in order, sorting any chunk that is out of order and
+// dropping bars already returned by an earlier chunk
+func stitchPriceChunks(chunks [][]PriceDataPoint) *PriceRange {
+	stitched := &PriceRange{}
+	seen := make(map[int64]bool)
+
+	for _, points := range chunks {
+		if !slices.IsSortedFunc(points, comparePriceDates) {
+			stitched.Unordered++
+			points = slices.Clone(points)
+			slices.SortStableFunc(points, comparePriceDates)
+		}
+
+		for _, point := range points {
+			if seen[point.Date] {
+				stitched.Duplicates++
+				continue
+			}
+			seen[point.Date] = true
+			stitched.Points = append(stitched.Points, point)
+		}
+	}
+
+	// Chunks may overlap, so the concatenation is sorted as a whole as well
+	slices.SortStableFunc(stitched.Points, comparePriceDates)
+	return stitched
+}
+
+// comparePriceDates orders bars by time, whether their dates are in seconds or milliseconds
+func comparePriceDates(a, b PriceDataPoint) int {
+	return a.Time().Compare(b.Time())
+}
+
+// barTradingTime returns the trading time a bar of length step covers: step itself within a
+// day, a session per day and five per week beyond
+func barTradingTime(step time.Duration, session MarketSession) time.Duration {
+	const day = 24 * time.Hour
+	switch {
+	case step < day:
+		return step
+	case step < 7*day:
+		return time.Duration(step/day) * session.regularLength()
+	default:
+		return time.Duration(5*step/(7*day)) * session.regularLength()
+	}
+}
+
+// findPriceGaps lists the stretches of [from, to] without bars that span more than threshold
+// of the session's trading time
+func findPriceGaps(points []PriceDataPoint, from, to time.Time, threshold time.Duration, session MarketSession) []PriceGap {
+	var gaps []PriceGap
+	previous := from
+	for _, point := range points {
+		at := point.Time()
+		if session.tradingTime(previous, at) > threshold {
+			gaps = append(gaps, PriceGap{previous, at})
+		}
+		previous = at
+	}
+	if session.tradingTime(previous, to) > threshold {
+		gaps = append(gaps, PriceGap{previous, to})
+	}
+	return gaps
+}
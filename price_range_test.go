@@ -0,0 +1,127 @@
+package laplace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCustomHistoricalPricesRange(t *testing.T) {
+	start := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	end := start.Add(10 * 24 * time.Hour)
+	outageFrom, outageTo := start.Add(54*time.Hour), start.Add(8*24*time.Hour+12*time.Hour)
+
+	var requests, reversed atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		q := r.URL.Query()
+		from, err := time.ParseInLocation(customHistoricalPriceLayout, q.Get("fromDate"), RegionUs.Location())
+		require.NoError(t, err)
+		to, err := time.ParseInLocation(customHistoricalPriceLayout, q.Get("toDate"), RegionUs.Location())
+		require.NoError(t, err)
+
+		// Hourly bars over the requested range, inclusive on both ends, except during the outage
+		points := []PriceDataPoint{}
+		for at := from; !at.After(to); at = at.Add(time.Hour) {
+			if at.Before(outageFrom) || !at.Before(outageTo) {
+				points = append(points, PriceDataPoint{Date: at.UnixMilli(), Close: 1})
+			}
+		}
+		// The first chunk arrives newest first
+		if from.Equal(start) {
+			reversed.Add(1)
+			slices.Reverse(points)
+		}
+		json.NewEncoder(w).Encode(points)
+	}))
+	defer srv.Close()
+
+	client := newOfflineTestClient(t, srv.URL)
+	result, err := client.GetCustomHistoricalPricesRange(context.Background(), "AAPL", RegionUs, start, end, HistoricalPriceIntervalOneHour, PriceRangeOptions{
+		ChunkSpan:         48 * time.Hour,
+		RequestsPerSecond: 1000,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 5, result.Chunks)
+	require.EqualValues(t, 5, requests.Load())
+	require.EqualValues(t, 1, reversed.Load())
+	require.Equal(t, 1, result.Unordered)
+
+	// Of the chunk boundaries at days 2, 4, 6 and 8, only the first one is outside the outage
+	require.Equal(t, 1, result.Duplicates)
+	require.True(t, slices.IsSortedFunc(result.Points, comparePriceDates))
+	require.Equal(t, start, result.Points[0].Time())
+	require.Equal(t, end, result.Points[len(result.Points)-1].Time())
+	require.Len(t, result.Points, 10*24+1-int(outageTo.Sub(outageFrom)/time.Hour))
+
+	require.Equal(t, []PriceGap{{outageFrom.Add(-time.Hour), outageTo}}, result.Gaps)
+
+	_, err = client.GetCustomHistoricalPricesRange(context.Background(), "AAPL", RegionUs, end, start, HistoricalPriceIntervalOneHour, PriceRangeOptions{})
+	require.ErrorContains(t, err, "invalid time range")
+}
+
+func TestGetCustomHistoricalPricesRangeSessionGaps(t *testing.T) {
+	istanbul := RegionTr.Location()
+	at := func(day, hour int) time.Time { return time.Date(2025, 3, day, hour, 0, 0, 0, istanbul) }
+
+	// Hourly bars from 10:00 to 17:00 on weekdays from Monday the 3rd to Monday the 10th, except
+	// on Wednesday, which is missing, and Thursday, whose session ends at 14:00
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		points := []PriceDataPoint{}
+		for day := 3; day <= 10; day++ {
+			if weekday := at(day, 0).Weekday(); weekday == time.Saturday || weekday == time.Sunday || day == 5 {
+				continue
+			}
+			for hour := 10; hour < 18; hour++ {
+				if day == 6 && hour >= 14 {
+					break
+				}
+				points = append(points, PriceDataPoint{Date: at(day, hour).UnixMilli(), Close: 1})
+			}
+		}
+		json.NewEncoder(w).Encode(points)
+	}))
+	defer srv.Close()
+
+	client := newOfflineTestClient(t, srv.URL)
+	result, err := client.GetCustomHistoricalPricesRange(context.Background(), "THYAO", RegionTr, at(3, 0), at(10, 18), HistoricalPriceIntervalOneHour, PriceRangeOptions{
+		ChunkSpan:         30 * 24 * time.Hour,
+		RequestsPerSecond: 1000,
+	})
+	require.NoError(t, err)
+
+	// Nights and the weekend are not gaps
+	require.Len(t, result.Gaps, 2)
+	require.True(t, result.Gaps[0].From.Equal(at(4, 17)))
+	require.True(t, result.Gaps[0].To.Equal(at(6, 10)))
+	require.True(t, result.Gaps[1].From.Equal(at(6, 13)))
+	require.True(t, result.Gaps[1].To.Equal(at(7, 10)))
+
+	// Daily bars are measured in sessions: a missing day is a gap, a weekend is not
+	session := DefaultMarketSession(RegionTr)
+	daily := []PriceDataPoint{{Date: at(3, 0).UnixMilli()}, {Date: at(4, 0).UnixMilli()}, {Date: at(6, 0).UnixMilli()}, {Date: at(7, 0).UnixMilli()}, {Date: at(10, 0).UnixMilli()}}
+	gaps := findPriceGaps(daily, at(3, 0), at(10, 0), barTradingTime(24*time.Hour, session)*3/2, session)
+	require.Len(t, gaps, 1)
+	require.True(t, gaps[0].From.Equal(at(4, 0)))
+	require.True(t, gaps[0].To.Equal(at(6, 0)))
+}
+
+func TestGetCustomHistoricalPricesRangeError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	client := newOfflineTestClient(t, srv.URL)
+	start := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	_, err := client.GetCustomHistoricalPricesRange(context.Background(), "AAPL", RegionUs, start, start.AddDate(0, 0, 30), HistoricalPriceIntervalOneMinute, PriceRangeOptions{Concurrency: 1, RequestsPerSecond: 1000})
+	require.ErrorContains(t, err, "failed to get AAPL prices from 2025-03-03T00:00:00Z")
+}
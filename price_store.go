@@ -0,0 +1,408 @@
+package laplace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const priceStoreFileVersion = 1
+
+// priceStoreTolerance is the relative difference between a stored and a refetched adjusted
+// price above which the adjustments are considered changed
+const priceStoreTolerance = 1e-6
+
+// PriceSeriesKey identifies a price series in a PriceStore.
+type PriceSeriesKey struct {
+	Symbol   string
+	Region   Region
+	Interval HistoricalPriceInterval
+}
+
+func (k PriceSeriesKey) String() string {
+	return fmt.Sprintf("%s/%s/%s", k.Region, k.Interval, k.Symbol)
+}
+
+// normalized returns the key with the symbol in the case it is stored under, so that keys
+// naming the same file are equal
+func (k PriceSeriesKey) normalized() PriceSeriesKey {
+	k.Symbol = strings.ToUpper(k.Symbol)
+	return k
+}
+
+// PriceStoreSync reports what a PriceStore sync did.
+type PriceStoreSync struct {
+	// Fetched is the number of bars downloaded
+	Fetched int
+	// Added is the number of bars the series grew by
+	Added int
+	// Rebuilt is set when refetched bars disagreed with the stored adjusted prices, which
+	// happens after a corporate action, and the series was downloaded again as a whole
+	Rebuilt bool
+}
+
+// priceStoreFile is the on-disk form of a series. From and To bound the time range that was
+// synced, which may extend beyond the first and last bar over closed sessions.
+type priceStoreFile struct {
+	Version  int                     `json:"version"`
+	Symbol   string                  `json:"symbol"`
+	Region   Region                  `json:"region"`
+	Interval HistoricalPriceInterval `json:"interval"`
+	From     time.Time               `json:"from"`
+	To       time.Time               `json:"to"`
+	Points   []PriceDataPoint        `json:"points"`
+}
+
+// PriceStore keeps historical price series on disk, one JSON file per symbol, region and
+// interval, and brings them up to date by downloading only the ranges it does not hold yet.
+// Adjusted and unadjusted prices are both kept. PriceStore is safe for concurrent use.
+type PriceStore struct {
+	dir string
+
+	mu    sync.Mutex
+	locks map[PriceSeriesKey]*sync.Mutex
+}
+
+// OpenPriceStore opens the store in dir, creating the directory if needed.
+func OpenPriceStore(dir string) (*PriceStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create price store: %w", err)
+	}
+	return &PriceStore{dir: dir, locks: make(map[PriceSeriesKey]*sync.Mutex)}, nil
+}
+
+// lock serializes the operations on one series
+func (s *PriceStore) lock(key PriceSeriesKey) func() {
+	key = key.normalized()
+
+	s.mu.Lock()
+	lock, ok := s.locks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.locks[key] = lock
+	}
+	s.mu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+// path returns the file of a series. Keys that would name a file outside the store, such as
+// a symbol containing a path separator or "..", are rejected.
+func (s *PriceStore) path(key PriceSeriesKey) (string, error) {
+	for _, element := range []string{key.Symbol, string(key.Region), string(key.Interval)} {
+		if element == "" || strings.ContainsAny(element, `/\`) || strings.Contains(element, "..") {
+			return "", fmt.Errorf("invalid price series key %s", key)
+		}
+	}
+	return filepath.Join(s.dir, string(key.Region), string(key.Interval), key.normalized().Symbol+".json"), nil
+}
+
+// Sync makes sure the series holds every bar of [from, to], downloading the ranges before and
+// after what is already stored through GetCustomHistoricalPricesRange. The stored bars at the
+// joins are downloaded again: the last one may have been incomplete, and if the others no
+// longer match, the adjustments changed and the whole series is rebuilt. A download without
+// bars leaves the synced range unchanged.
+func (s *PriceStore) Sync(ctx context.Context, client *Client, key PriceSeriesKey, from, to time.Time, opts PriceRangeOptions) (*PriceStoreSync, error) {
+	if from.After(to) {
+		return nil, fmt.Errorf("invalid time range, %s is after %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	unlock := s.lock(key)
+	defer unlock()
+
+	series, err := s.read(key)
+	if err != nil {
+		return nil, err
+	}
+
+	fetch := func(from, to time.Time) ([]PriceDataPoint, error) {
+		result, err := client.GetCustomHistoricalPricesRange(ctx, key.Symbol, key.Region, from, to, key.Interval, opts)
+		if err != nil {
+			return nil, err
+		}
+		return result.Points, nil
+	}
+
+	report := &PriceStoreSync{}
+	before := len(series.Points)
+
+	// rebuild is set when refetched bars disagree with the stored ones
+	rebuild := false
+
+	if len(series.Points) == 0 || series.From.IsZero() {
+		// Nothing was synced yet, though bars may have been put
+		points, err := fetch(from, to)
+		if err != nil {
+			return nil, err
+		}
+		report.Fetched = len(points)
+		series.Points = mergePricePoints(series.Points, points)
+		// Without bars nothing is known about the range, so a range synced before is kept
+		if len(points) > 0 {
+			series.From, series.To = from, to
+		}
+	} else {
+		if from.Before(series.From) {
+			// The first stored bar is fetched again to join the ranges and check the adjustments
+			points, err := fetch(from, series.Points[0].Time())
+			if err != nil {
+				return nil, err
+			}
+			report.Fetched += len(points)
+
+			if adjustmentsChanged(series.Points[:1], points) {
+				rebuild = true
+			} else if len(points) > 0 {
+				series.Points = mergePricePoints(series.Points, points)
+				series.From = from
+			}
+		}
+
+		if !rebuild && to.After(series.To) {
+			// The last two stored bars are fetched again: the last one to complete it, the one
+			// before it to check the adjustments
+			overlap := series.Points[max(len(series.Points)-2, 0)]
+			points, err := fetch(overlap.Time(), to)
+			if err != nil {
+				return nil, err
+			}
+			report.Fetched += len(points)
+
+			if adjustmentsChanged(series.Points[:len(series.Points)-1], points) {
+				rebuild = true
+			} else if len(points) > 0 {
+				series.Points = mergePricePoints(series.Points, points)
+				series.To = to
+			}
+		}
+
+		if rebuild {
+			from, to := minTime(from, series.From), maxTime(to, series.To)
+			points, err := fetch(from, to)
+			if err != nil {
+				return nil, err
+			}
+			if len(points) == 0 {
+				return nil, fmt.Errorf("failed to rebuild price series %s: no bars returned", key)
+			}
+			report.Fetched += len(points)
+			report.Rebuilt = true
+			series.Points, series.From, series.To = points, from, to
+		}
+	}
+
+	report.Added = len(series.Points) - before
+	if err := s.write(key, series); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Rebuild downloads the whole synced range of a series again, for instance after a corporate
+// action that Sync could not detect because no new bars were requested.
+func (s *PriceStore) Rebuild(ctx context.Context, client *Client, key PriceSeriesKey, opts PriceRangeOptions) (*PriceStoreSync, error) {
+	unlock := s.lock(key)
+	defer unlock()
+
+	series, err := s.read(key)
+	if err != nil {
+		return nil, err
+	}
+	if series.From.IsZero() {
+		return nil, fmt.Errorf("price series %s is not stored", key)
+	}
+
+	result, err := client.GetCustomHistoricalPricesRange(ctx, key.Symbol, key.Region, series.From, series.To, key.Interval, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Points) == 0 {
+		return nil, fmt.Errorf("failed to rebuild price series %s: no bars returned", key)
+	}
+
+	report := &PriceStoreSync{Fetched: len(result.Points), Added: len(result.Points) - len(series.Points), Rebuilt: true}
+	series.Points = result.Points
+	if err := s.write(key, series); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Put merges bars obtained elsewhere, such as from GetHistoricalPrices, into a series. Bars
+// replace stored bars with the same date. The synced range is left unchanged, so Sync still
+// downloads what lies outside it.
+func (s *PriceStore) Put(key PriceSeriesKey, points []PriceDataPoint) error {
+	unlock := s.lock(key)
+	defer unlock()
+
+	series, err := s.read(key)
+	if err != nil {
+		return err
+	}
+
+	series.Points = mergePricePoints(series.Points, points)
+	return s.write(key, series)
+}
+
+// Range returns the stored bars of a series within [from, to]. A zero from or to leaves that
+// end open; a series that was never stored has no bars.
+func (s *PriceStore) Range(key PriceSeriesKey, from, to time.Time) ([]PriceDataPoint, error) {
+	unlock := s.lock(key)
+	defer unlock()
+
+	series, err := s.read(key)
+	if err != nil {
+		return nil, err
+	}
+
+	first := 0
+	if !from.IsZero() {
+		first = sort.Search(len(series.Points), func(i int) bool { return !series.Points[i].Time().Before(from) })
+	}
+	last := len(series.Points)
+	if !to.IsZero() {
+		last = sort.Search(len(series.Points), func(i int) bool { return series.Points[i].Time().After(to) })
+	}
+	if first >= last {
+		return []PriceDataPoint{}, nil
+	}
+	return slices.Clone(series.Points[first:last]), nil
+}
+
+// Coverage returns the time range a series was synced over, or zero times if it never was.
+func (s *PriceStore) Coverage(key PriceSeriesKey) (time.Time, time.Time, error) {
+	unlock := s.lock(key)
+	defer unlock()
+
+	series, err := s.read(key)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return series.From, series.To, nil
+}
+
+// Delete removes a series from the store.
+func (s *PriceStore) Delete(key PriceSeriesKey) error {
+	unlock := s.lock(key)
+	defer unlock()
+
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// read loads a series, returning an empty one if it is not stored
+func (s *PriceStore) read(key PriceSeriesKey) (*priceStoreFile, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &priceStoreFile{
+		Version:  priceStoreFileVersion,
+		Symbol:   strings.ToUpper(key.Symbol),
+		Region:   key.Region,
+		Interval: key.Interval,
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return series, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if err := json.NewDecoder(file).Decode(series); err != nil {
+		return nil, fmt.Errorf("failed to read price series %s: %w", key, err)
+	}
+	if series.Version != priceStoreFileVersion {
+		return nil, fmt.Errorf("unsupported price series version: %d", series.Version)
+	}
+	return series, nil
+}
+
+// write saves a series, replacing its file atomically
+func (s *PriceStore) write(key PriceSeriesKey, series *priceStoreFile) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := json.NewEncoder(tmp).Encode(series); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write price series %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// mergePricePoints merges two ordered series; bars of added replace bars of stored with the
+// same date
+func mergePricePoints(stored, added []PriceDataPoint) []PriceDataPoint {
+	byDate := make(map[int64]PriceDataPoint, len(stored)+len(added))
+	for _, point := range stored {
+		byDate[point.Date] = point
+	}
+	for _, point := range added {
+		byDate[point.Date] = point
+	}
+
+	merged := make([]PriceDataPoint, 0, len(byDate))
+	for _, point := range byDate {
+		merged = append(merged, point)
+	}
+	slices.SortFunc(merged, comparePriceDates)
+	return merged
+}
+
+// adjustmentsChanged reports whether any refetched bar has adjusted prices that differ from the
+// stored bar with the same date
+func adjustmentsChanged(stored, refetched []PriceDataPoint) bool {
+	byDate := make(map[int64]PriceDataPoint, len(refetched))
+	for _, point := range refetched {
+		byDate[point.Date] = point
+	}
+
+	for _, old := range stored {
+		point, ok := byDate[old.Date]
+		if !ok {
+			continue
+		}
+		if !pricesClose(old.Close, point.Close) || !pricesClose(old.Open, point.Open) {
+			return true
+		}
+	}
+	return false
+}
+
+func pricesClose(a, b float64) bool {
+	return math.Abs(a-b) <= priceStoreTolerance*math.Max(math.Abs(a), math.Abs(b))
+}
@@ -0,0 +1,207 @@
+package laplace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceStoreSync(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := func(i int) time.Time { return start.AddDate(0, 0, i) }
+
+	// Daily bars closing at 100 + i; factor adjusts the bars before day 15, as after a split
+	var factor atomic.Value
+	factor.Store(1.0)
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		from, err := time.ParseInLocation(customHistoricalPriceLayout, q.Get("fromDate"), RegionTr.Location())
+		require.NoError(t, err)
+		to, err := time.ParseInLocation(customHistoricalPriceLayout, q.Get("toDate"), RegionTr.Location())
+		require.NoError(t, err)
+		requested = append(requested, from.UTC().Format("01-02")+"/"+to.UTC().Format("01-02"))
+
+		points := []PriceDataPoint{}
+		for i := 0; i < 30; i++ {
+			if at := day(i); !at.Before(from) && !at.After(to) {
+				close := 100 + float64(i)
+				if i < 15 {
+					close *= factor.Load().(float64)
+				}
+				points = append(points, PriceDataPoint{Date: at.UnixMilli(), Close: close, UnadjustedClose: 100 + float64(i)})
+			}
+		}
+		json.NewEncoder(w).Encode(points)
+	}))
+	defer srv.Close()
+
+	client := newOfflineTestClient(t, srv.URL)
+	ctx := context.Background()
+	dir := t.TempDir()
+	key := PriceSeriesKey{Symbol: "thyao", Region: RegionTr, Interval: HistoricalPriceIntervalOneDay}
+	opts := PriceRangeOptions{RequestsPerSecond: 1000}
+
+	store, err := OpenPriceStore(dir)
+	require.NoError(t, err)
+
+	report, err := store.Sync(ctx, client, key, day(5), day(9), opts)
+	require.NoError(t, err)
+	require.Equal(t, &PriceStoreSync{Fetched: 5, Added: 5}, report)
+
+	// Only the missing ends are downloaded, overlapping the stored bars at the joins
+	requested = nil
+	report, err = store.Sync(ctx, client, key, day(3), day(12), opts)
+	require.NoError(t, err)
+	require.Equal(t, []string{"01-04/01-06", "01-09/01-13"}, requested)
+	require.Equal(t, &PriceStoreSync{Fetched: 8, Added: 5}, report)
+
+	// A synced range is not downloaded again
+	requested = nil
+	_, err = store.Sync(ctx, client, key, day(4), day(10), opts)
+	require.NoError(t, err)
+	require.Empty(t, requested)
+
+	// The store persists across opens
+	store, err = OpenPriceStore(dir)
+	require.NoError(t, err)
+	points, err := store.Range(key, day(10), day(11))
+	require.NoError(t, err)
+	require.Len(t, points, 2)
+	require.Equal(t, 110.0, points[0].Close)
+	require.Equal(t, 110.0, points[0].UnadjustedClose)
+
+	from, to, err := store.Coverage(key)
+	require.NoError(t, err)
+	require.Equal(t, day(3), from.UTC())
+	require.Equal(t, day(12), to.UTC())
+
+	// A split halves the adjusted history, which the refetched overlap reveals
+	factor.Store(0.5)
+	requested = nil
+	report, err = store.Sync(ctx, client, key, day(3), day(16), opts)
+	require.NoError(t, err)
+	require.True(t, report.Rebuilt)
+	require.Equal(t, []string{"01-12/01-17", "01-04/01-17"}, requested)
+
+	points, err = store.Range(key, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, points, 14)
+	require.Equal(t, 51.5, points[0].Close)
+	require.Equal(t, 103.0, points[0].UnadjustedClose)
+	require.Equal(t, 116.0, points[13].Close)
+
+	// Extending the series backwards checks the adjustments on the joining bar as well
+	factor.Store(0.25)
+	requested = nil
+	report, err = store.Sync(ctx, client, key, day(1), day(16), opts)
+	require.NoError(t, err)
+	require.True(t, report.Rebuilt)
+	require.Equal(t, []string{"01-02/01-04", "01-02/01-17"}, requested)
+
+	points, err = store.Range(key, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, points, 16)
+	require.Equal(t, 25.25, points[0].Close)
+	require.Equal(t, 25.75, points[2].Close)
+	require.Equal(t, 116.0, points[15].Close)
+
+	// Bars from other sources are merged by date
+	require.NoError(t, store.Put(key, []PriceDataPoint{{Date: day(3).UnixMilli(), Close: 1}, {Date: day(0).UnixMilli(), Close: 2}}))
+	points, err = store.Range(key, time.Time{}, day(3))
+	require.NoError(t, err)
+	require.Equal(t, []float64{2, 1}, []float64{points[0].Close, points[len(points)-1].Close})
+
+	require.NoError(t, store.Delete(key))
+	points, err = store.Range(key, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Empty(t, points)
+}
+
+func TestPriceStoreRejectsPathsOutsideTheStore(t *testing.T) {
+	store, err := OpenPriceStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, symbol := range []string{"../THYAO", `..\THYAO`, "BRK/B", ".."} {
+		key := PriceSeriesKey{Symbol: symbol, Region: RegionTr, Interval: HistoricalPriceIntervalOneDay}
+		require.ErrorContains(t, store.Put(key, []PriceDataPoint{{Date: 1, Close: 1}}), "invalid price series key", symbol)
+		_, err := store.Range(key, time.Time{}, time.Time{})
+		require.Error(t, err)
+		require.Error(t, store.Delete(key))
+	}
+
+	_, err = store.Range(PriceSeriesKey{Symbol: "THYAO", Region: "..", Interval: HistoricalPriceIntervalOneDay}, time.Time{}, time.Time{})
+	require.Error(t, err)
+}
+
+func TestPriceStoreSyncWithoutBars(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := func(i int) time.Time { return start.AddDate(0, 0, i) }
+
+	var empty atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		from, err := time.ParseInLocation(customHistoricalPriceLayout, q.Get("fromDate"), RegionTr.Location())
+		require.NoError(t, err)
+		to, err := time.ParseInLocation(customHistoricalPriceLayout, q.Get("toDate"), RegionTr.Location())
+		require.NoError(t, err)
+
+		points := []PriceDataPoint{}
+		for i := 0; i < 30 && !empty.Load(); i++ {
+			if at := day(i); !at.Before(from) && !at.After(to) {
+				points = append(points, PriceDataPoint{Date: at.UnixMilli(), Close: 100})
+			}
+		}
+		json.NewEncoder(w).Encode(points)
+	}))
+	defer srv.Close()
+
+	client := newOfflineTestClient(t, srv.URL)
+	ctx := context.Background()
+	store, err := OpenPriceStore(t.TempDir())
+	require.NoError(t, err)
+	key := PriceSeriesKey{Symbol: "THYAO", Region: RegionTr, Interval: HistoricalPriceIntervalOneDay}
+	opts := PriceRangeOptions{RequestsPerSecond: 1000}
+
+	_, err = store.Sync(ctx, client, key, day(0), day(4), opts)
+	require.NoError(t, err)
+
+	empty.Store(true)
+	report, err := store.Sync(ctx, client, key, day(0), day(9), opts)
+	require.NoError(t, err)
+	require.Zero(t, report.Fetched)
+
+	from, to, err := store.Coverage(key)
+	require.NoError(t, err)
+	require.Equal(t, day(0), from.UTC())
+	require.Equal(t, day(4), to.UTC())
+
+	_, err = store.Rebuild(ctx, client, key, opts)
+	require.ErrorContains(t, err, "no bars returned")
+	points, err := store.Range(key, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, points, 5)
+
+	// A series synced without bars has no range
+	other := PriceSeriesKey{Symbol: "GARAN", Region: RegionTr, Interval: HistoricalPriceIntervalOneDay}
+	_, err = store.Sync(ctx, client, other, day(0), day(4), opts)
+	require.NoError(t, err)
+	from, to, err = store.Coverage(other)
+	require.NoError(t, err)
+	require.True(t, from.IsZero() && to.IsZero())
+}
+
+func TestPriceStoreLocksBySymbolCaseInsensitively(t *testing.T) {
+	store, err := OpenPriceStore(t.TempDir())
+	require.NoError(t, err)
+
+	store.lock(PriceSeriesKey{Symbol: "thyao", Region: RegionTr, Interval: HistoricalPriceIntervalOneDay})()
+	store.lock(PriceSeriesKey{Symbol: "THYAO", Region: RegionTr, Interval: HistoricalPriceIntervalOneDay})()
+	require.Len(t, store.locks, 1)
+}
@@ -0,0 +1,85 @@
+package laplace
+
+import (
+	"context"
+	"fmt"
+	"time"
+	// Exchange time zones must resolve on hosts without a zoneinfo database
+	_ "time/tzdata"
+)
+
+// customHistoricalPriceLayout is the date format of the custom historical prices endpoint,
+// interpreted in the exchange's time zone
+const customHistoricalPriceLayout = "2006-01-02 15:04:05"
+
+// millisecondDates is the smallest Unix timestamp read as milliseconds; smaller values are
+// read as seconds. As seconds it would lie in the year 5138, as milliseconds in 1973.
+const millisecondDates = 100_000_000_000
+
+var (
+	istanbul = mustLoadLocation("Europe/Istanbul")
+	newYork  = mustLoadLocation("America/New_York")
+)
+
+func mustLoadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(fmt.Sprintf("failed to load time zone %s: %v", name, err))
+	}
+	return location
+}
+
+// Location returns the time zone of the region's exchange: Europe/Istanbul for RegionTr and
+// America/New_York for RegionUs. Other regions use UTC.
+func (r Region) Location() *time.Location {
+	switch r {
+	case RegionTr:
+		return istanbul
+	case RegionUs:
+		return newYork
+	}
+	return time.UTC
+}
+
+// GetCustomHistoricalPricesBetween is GetCustomHistoricalPrices with a time range. from and to
+// may be in any time zone; they are converted to the exchange's local time, which is what the
+// endpoint expects, so ranges stay correct across daylight saving changes.
+func (c *Client) GetCustomHistoricalPricesBetween(ctx context.Context, symbol string, region Region, from, to time.Time, interval HistoricalPriceInterval, detail bool, numIntervals ...int) ([]PriceDataPoint, error) {
+	if from.After(to) {
+		return []PriceDataPoint{}, fmt.Errorf("invalid time range, %s is after %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	location := region.Location()
+	return c.GetCustomHistoricalPrices(
+		ctx,
+		symbol,
+		region,
+		from.In(location).Format(customHistoricalPriceLayout),
+		to.In(location).Format(customHistoricalPriceLayout),
+		interval,
+		detail,
+		numIntervals...,
+	)
+}
+
+// Time returns the start of the bar as an instant in UTC. Dates are Unix timestamps in
+// milliseconds; timestamps in seconds are recognized by their magnitude and read as such.
+func (p PriceDataPoint) Time() time.Time {
+	return unixTime(p.Date).UTC()
+}
+
+// unixTime converts a Unix timestamp in milliseconds, or in seconds as recognized by its
+// magnitude, to a time.Time. Every timestamp of the API is read through it, so that a value
+// means the same instant everywhere.
+func unixTime(timestamp int64) time.Time {
+	if timestamp < millisecondDates && timestamp > -millisecondDates {
+		return time.Unix(timestamp, 0)
+	}
+	return time.UnixMilli(timestamp)
+}
+
+// ExchangeTime returns the start of the bar in the time zone of the region's exchange, see
+// Region.Location.
+func (p PriceDataPoint) ExchangeTime(region Region) time.Time {
+	return p.Time().In(region.Location())
+}
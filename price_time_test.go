@@ -0,0 +1,60 @@
+package laplace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCustomHistoricalPricesBetween(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		got = append(got, q.Get("fromDate"), q.Get("toDate"))
+		json.NewEncoder(w).Encode([]PriceDataPoint{{Date: 1741613400000, Close: 1}})
+	}))
+	defer srv.Close()
+
+	client := newOfflineTestClient(t, srv.URL)
+	ctx := context.Background()
+
+	// New York switched to daylight saving time on 9 March 2025, Istanbul keeps UTC+3
+	from := time.Date(2025, 3, 7, 14, 30, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 10, 13, 30, 0, 0, time.UTC)
+
+	_, err := client.GetCustomHistoricalPricesBetween(ctx, "AAPL", RegionUs, from, to, HistoricalPriceIntervalOneHour, false)
+	require.NoError(t, err)
+	_, err = client.GetCustomHistoricalPricesBetween(ctx, "THYAO", RegionTr, from, to, HistoricalPriceIntervalOneHour, false)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"2025-03-07 09:30:00", "2025-03-10 09:30:00",
+		"2025-03-07 17:30:00", "2025-03-10 16:30:00",
+	}, got)
+
+	_, err = client.GetCustomHistoricalPricesBetween(ctx, "AAPL", RegionUs, to, from, HistoricalPriceIntervalOneHour, false)
+	require.ErrorContains(t, err, "invalid time range")
+}
+
+func TestPriceDataPointTime(t *testing.T) {
+	open := time.Date(2025, 3, 10, 13, 30, 0, 0, time.UTC)
+
+	point := PriceDataPoint{Date: open.UnixMilli()}
+	require.Equal(t, open, point.Time())
+	require.Equal(t, open, PriceDataPoint{Date: open.Unix()}.Time())
+
+	// Live message dates are read with the same units
+	for _, date := range []int64{open.UnixMilli(), open.Unix(), 500_000_000_000} {
+		require.True(t, liveDateToTime(date).Equal(PriceDataPoint{Date: date}.Time()), date)
+	}
+
+	local := point.ExchangeTime(RegionUs)
+	require.Equal(t, "2025-03-10 09:30:00 EDT", local.Format("2006-01-02 15:04:05 MST"))
+	require.Equal(t, "2025-03-10 16:30:00 +03", point.ExchangeTime(RegionTr).Format("2006-01-02 15:04:05 MST"))
+	require.Equal(t, time.UTC, RegionNone.Location())
+}
@@ -0,0 +1,447 @@
+package laplace
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// SessionBreak is a pause in trading within a session, as offsets from local midnight.
+type SessionBreak struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// BISTLunchBreak is the midday break Borsa Istanbul sessions had. Add it to the Breaks of a
+// MarketSession when resampling bars from periods that had it.
+var BISTLunchBreak = SessionBreak{Start: 12*time.Hour + 30*time.Minute, End: 14 * time.Hour}
+
+// MarketSession describes the trading hours of an exchange: when it opens and closes, in local
+// time as offsets from midnight, and the breaks, half days and holidays in between. Saturdays
+// and Sundays are never traded.
+type MarketSession struct {
+	Location *time.Location
+	Open     time.Duration
+	Close    time.Duration
+	Breaks   []SessionBreak
+	// HalfDays maps dates (YYYY-MM-DD) to their early close
+	HalfDays map[string]time.Duration
+	// Holidays holds the dates (YYYY-MM-DD) without trading
+	Holidays map[string]bool
+}
+
+// DefaultMarketSession returns the regular session of a region's exchange: 10:00 to 18:00 in
+// Istanbul for RegionTr and 9:30 to 16:00 in New York for RegionUs. Half days and holidays
+// are not included, as they change every year.
+func DefaultMarketSession(region Region) MarketSession {
+	switch region {
+	case RegionUs:
+		return MarketSession{Location: region.Location(), Open: 9*time.Hour + 30*time.Minute, Close: 16 * time.Hour}
+	default:
+		return MarketSession{Location: region.Location(), Open: 10 * time.Hour, Close: 18 * time.Hour}
+	}
+}
+
+// sessionSegment is a stretch of continuous trading
+type sessionSegment struct {
+	start time.Time
+	end   time.Time
+}
+
+// TradingDay reports whether date, in the session's time zone, is traded.
+func (s MarketSession) TradingDay(date time.Time) bool {
+	date = date.In(s.location())
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return false
+	}
+	return !s.Holidays[date.Format(time.DateOnly)]
+}
+
+// tradingTime returns how much of (from, to) falls within the trading stretches of traded days
+func (s MarketSession) tradingTime(from, to time.Time) time.Duration {
+	var total time.Duration
+	location := s.location()
+	for day := from.In(location); ; day = day.AddDate(0, 0, 1) {
+		day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, location)
+		if !day.Before(to) {
+			break
+		}
+		if !s.TradingDay(day) {
+			continue
+		}
+		for _, segment := range s.segments(day) {
+			start, end := maxTime(segment.start, from), minTime(segment.end, to)
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+	}
+	return total
+}
+
+// regularLength returns the trading time of a regular, full day
+func (s MarketSession) regularLength() time.Duration {
+	regular := MarketSession{Location: time.UTC, Open: s.Open, Close: s.Close, Breaks: s.Breaks}
+	monday := time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return regular.tradingTime(monday, monday.AddDate(0, 0, 1))
+}
+
+func (s MarketSession) location() *time.Location {
+	if s.Location == nil {
+		return time.UTC
+	}
+	return s.Location
+}
+
+// segments returns the continuous trading stretches of the day of t, regardless of whether
+// the day is traded
+func (s MarketSession) segments(t time.Time) []sessionSegment {
+	t = t.In(s.location())
+	at := func(offset time.Duration) time.Time {
+		// time.Date reads the offset as wall clock time, which differs from the time elapsed
+		// since midnight on daylight saving days
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, int(offset), s.location())
+	}
+
+	closing := s.Close
+	if early, ok := s.HalfDays[t.Format(time.DateOnly)]; ok {
+		closing = early
+	}
+
+	var segments []sessionSegment
+	start := s.Open
+	breaks := slices.Clone(s.Breaks)
+	slices.SortFunc(breaks, func(a, b SessionBreak) int { return cmp.Compare(a.Start, b.Start) })
+	for _, pause := range breaks {
+		if pause.Start >= closing {
+			break
+		}
+		if pause.Start > start {
+			segments = append(segments, sessionSegment{at(start), at(pause.Start)})
+		}
+		start = max(start, pause.End)
+	}
+	if closing > start {
+		segments = append(segments, sessionSegment{at(start), at(closing)})
+	}
+	return segments
+}
+
+// bucket returns the start of the bucket of length every that t falls in. Buckets are aligned
+// to the start of each trading stretch and end with it, so that no bucket spans a break or the
+// close. Bars outside the stretches, such as auction prints, join the bucket before them, or
+// the first bucket of the day when they precede the open.
+func (s MarketSession) bucket(t time.Time, every time.Duration) time.Time {
+	segments := s.segments(t)
+	if len(segments) == 0 {
+		return t.Truncate(every)
+	}
+
+	segment := segments[0]
+	for _, candidate := range segments {
+		if candidate.start.After(t) {
+			break
+		}
+		segment = candidate
+	}
+
+	offset := t.Sub(segment.start)
+	if offset < 0 {
+		return segment.start
+	}
+	last := (segment.end.Sub(segment.start) - 1) / every
+	return segment.start.Add(min(offset/every, last) * every)
+}
+
+// ResampleIntraday aggregates intraday bars into buckets of length every, aligned to the
+// trading stretches of session: with every set to an hour and a 9:30 open, buckets start at
+// 9:30, 10:30 and so on, and the last one of the day ends at the close. Each bucket takes the
+// open of its first bar, the close of its last one, the highest high, the lowest low and the
+// summed volume, for the adjusted and unadjusted prices alike. Bars must be ordered by date.
+func ResampleIntraday(points []PriceDataPoint, every time.Duration, session MarketSession) ([]PriceDataPoint, error) {
+	if every <= 0 {
+		return nil, fmt.Errorf("invalid resampling interval %s", every)
+	}
+	return resample(points, func(t time.Time) time.Time {
+		return session.bucket(t, every)
+	})
+}
+
+// CalendarPeriod is a calendar period daily bars are resampled to.
+type CalendarPeriod string
+
+const (
+	CalendarWeek    CalendarPeriod = "week"
+	CalendarMonth   CalendarPeriod = "month"
+	CalendarQuarter CalendarPeriod = "quarter"
+	CalendarYear    CalendarPeriod = "year"
+)
+
+// ResampleCalendar aggregates bars into calendar weeks (starting on Monday), months, quarters
+// or years in the given time zone, as ResampleIntraday does. The resampled bars are dated at
+// local midnight of the first day of their period. Bars must be ordered by date.
+func ResampleCalendar(points []PriceDataPoint, period CalendarPeriod, location *time.Location) ([]PriceDataPoint, error) {
+	var start func(year int, month time.Month, day int, weekday time.Weekday) (int, time.Month, int)
+	switch period {
+	case CalendarWeek:
+		start = func(year int, month time.Month, day int, weekday time.Weekday) (int, time.Month, int) {
+			return year, month, day - (int(weekday)+6)%7
+		}
+	case CalendarMonth:
+		start = func(year int, month time.Month, _ int, _ time.Weekday) (int, time.Month, int) {
+			return year, month, 1
+		}
+	case CalendarQuarter:
+		start = func(year int, month time.Month, _ int, _ time.Weekday) (int, time.Month, int) {
+			return year, month - (month-1)%3, 1
+		}
+	case CalendarYear:
+		start = func(year int, _ time.Month, _ int, _ time.Weekday) (int, time.Month, int) {
+			return year, time.January, 1
+		}
+	default:
+		return nil, fmt.Errorf("invalid calendar period %q", period)
+	}
+
+	return resample(points, func(t time.Time) time.Time {
+		t = t.In(location)
+		year, month, day := start(t.Year(), t.Month(), t.Day(), t.Weekday())
+		return time.Date(year, month, day, 0, 0, 0, 0, location)
+	})
+}
+
+// resample aggregates consecutive bars with the same bucket
+func resample(points []PriceDataPoint, bucket func(time.Time) time.Time) ([]PriceDataPoint, error) {
+	if !slices.IsSortedFunc(points, comparePriceDates) {
+		return nil, fmt.Errorf("bars are not ordered by date")
+	}
+
+	var resampled []PriceDataPoint
+	var current int64
+	for _, point := range points {
+		start := bucket(point.Time()).UnixMilli()
+		if len(resampled) == 0 || start != current {
+			current = start
+			point.Date = start
+			resampled = append(resampled, point)
+			continue
+		}
+
+		bar := &resampled[len(resampled)-1]
+		bar.High = max(bar.High, point.High)
+		bar.Low = min(bar.Low, point.Low)
+		bar.Close = point.Close
+		bar.Volume += point.Volume
+		bar.UnadjustedHigh = max(bar.UnadjustedHigh, point.UnadjustedHigh)
+		bar.UnadjustedLow = min(bar.UnadjustedLow, point.UnadjustedLow)
+		bar.UnadjustedClose = point.UnadjustedClose
+		bar.UnadjustedVol += point.UnadjustedVol
+	}
+	return resampled, nil
+}
+
+// FillSessionGaps forward-fills intraday bars: every bucket of length every within the trading
+// stretches of session, from the first bar to the last, that has no bar gets one at the
+// previous close with no volume. Nights, weekends, holidays and breaks are not filled. Bars
+// must be ordered by date and aligned to the buckets, as ResampleIntraday returns them.
+func FillSessionGaps(points []PriceDataPoint, every time.Duration, session MarketSession) ([]PriceDataPoint, error) {
+	if every <= 0 {
+		return nil, fmt.Errorf("invalid fill interval %s", every)
+	}
+	if !slices.IsSortedFunc(points, comparePriceDates) {
+		return nil, fmt.Errorf("bars are not ordered by date")
+	}
+	if len(points) == 0 {
+		return points, nil
+	}
+
+	first, last := points[0].Time(), points[len(points)-1].Time()
+	var expected []time.Time
+	location := session.location()
+	for day := first.In(location); ; day = day.AddDate(0, 0, 1) {
+		day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, location)
+		if day.After(last) {
+			break
+		}
+		if !session.TradingDay(day) {
+			continue
+		}
+		for _, segment := range session.segments(day) {
+			for at := segment.start; at.Before(segment.end); at = at.Add(every) {
+				if !at.Before(first) && !at.After(last) {
+					expected = append(expected, at)
+				}
+			}
+		}
+	}
+
+	filled := make([]PriceDataPoint, 0, max(len(points), len(expected)))
+	i := 0
+	// Bars are compared by time, as their dates may be in seconds or milliseconds
+	for _, at := range expected {
+		for i < len(points) && points[i].Time().Before(at) {
+			filled = append(filled, points[i])
+			i++
+		}
+		if i < len(points) && points[i].Time().Equal(at) {
+			filled = append(filled, points[i])
+			i++
+			continue
+		}
+
+		previous := filled[len(filled)-1]
+		filled = append(filled, carryForward(previous, at.UnixMilli()))
+	}
+	filled = append(filled, points[i:]...)
+
+	return filled, nil
+}
+
+// AlignFill is how aligned series treat a date some of them have no bar at.
+type AlignFill string
+
+const (
+	// AlignFillNone keeps every date and leaves the missing bars empty
+	AlignFillNone AlignFill = "none"
+	// AlignFillForward keeps every date and repeats the previous close of a series where it
+	// has no bar, leaving the bars before its first one empty
+	AlignFillForward AlignFill = "forward"
+	// AlignFillDrop keeps only the dates every series has a bar at
+	AlignFillDrop AlignFill = "drop"
+)
+
+func (f AlignFill) valid() bool {
+	return f == AlignFillNone || f == AlignFillForward || f == AlignFillDrop
+}
+
+// AlignedSeries is a set of price series sharing the same dates.
+type AlignedSeries struct {
+	// Dates are the midnights of the aligned calendar dates, or the aligned bar times when the
+	// series were aligned without a location
+	Dates []time.Time
+	// Series holds the bars of every symbol, one per date. A bar is the zero PriceDataPoint,
+	// apart from its Date, where a symbol has none and none is filled in.
+	Series map[string][]PriceDataPoint
+}
+
+// AlignSeries aligns the series of several symbols on the calendar dates of their bars in
+// location, so that daily bars of different exchanges or sources, stamped at different times
+// of day, line up; location must be one where every bar falls on its trading date. A series
+// with several bars on a date is represented by the last one. With a nil location, bars are
+// instead matched on their exact time, as intraday bars of one exchange are. fill decides what
+// happens to the dates some series have no bar at; filled bars repeat the previous close with
+// no volume. Series must be ordered by date.
+func AlignSeries(series map[string][]PriceDataPoint, fill AlignFill, location *time.Location) (*AlignedSeries, error) {
+	symbols := make([]string, 0, len(series))
+	columns := make([][]PriceDataPoint, 0, len(series))
+	for symbol, points := range series {
+		symbols = append(symbols, symbol)
+		columns = append(columns, points)
+	}
+
+	index, rows, err := alignIndex(symbols, columns, location, fill)
+	if err != nil {
+		return nil, err
+	}
+
+	aligned := &AlignedSeries{Dates: index, Series: make(map[string][]PriceDataPoint, len(series))}
+	for j, symbol := range symbols {
+		out := make([]PriceDataPoint, len(index))
+		var previous *PriceDataPoint
+		for i, at := range index {
+			switch {
+			case rows[j][i] >= 0:
+				previous = &columns[j][rows[j][i]]
+				out[i] = *previous
+			case previous != nil && fill == AlignFillForward:
+				out[i] = carryForward(*previous, at.UnixMilli())
+			default:
+				out[i] = PriceDataPoint{Date: at.UnixMilli()}
+			}
+		}
+		aligned.Series[symbol] = out
+	}
+
+	return aligned, nil
+}
+
+// alignIndex matches the bars of several series, on their calendar date in location or, with
+// a nil location, on their time. The index holds every date any series has, less those fill
+// drops; rows[j][i] is the position in columns[j] of the bar at index[i], or -1 if there is
+// none. It is shared by AlignSeries and PriceMatrix so that both align alike.
+func alignIndex(names []string, columns [][]PriceDataPoint, location *time.Location, fill AlignFill) ([]time.Time, [][]int, error) {
+	if !fill.valid() {
+		return nil, nil, fmt.Errorf("invalid align fill %q", fill)
+	}
+
+	key := func(point PriceDataPoint) time.Time {
+		at := point.Time()
+		if location == nil {
+			return at.UTC()
+		}
+		at = at.In(location)
+		return time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, location)
+	}
+
+	positions := make([]map[int64]int, len(columns))
+	counts := make(map[int64]int)
+	keys := make(map[int64]time.Time)
+	for j, points := range columns {
+		if !slices.IsSortedFunc(points, comparePriceDates) {
+			return nil, nil, fmt.Errorf("bars of %s are not ordered by date", names[j])
+		}
+		positions[j] = make(map[int64]int, len(points))
+		for i, point := range points {
+			at := key(point)
+			// Seconds and milliseconds dates are told apart by Time, so keys are compared in
+			// milliseconds
+			ms := at.UnixMilli()
+			if _, ok := positions[j][ms]; !ok {
+				counts[ms]++
+			}
+			positions[j][ms] = i
+			keys[ms] = at
+		}
+	}
+
+	var dates []int64
+	for date, count := range counts {
+		if fill != AlignFillDrop || count == len(columns) {
+			dates = append(dates, date)
+		}
+	}
+	slices.Sort(dates)
+
+	index := make([]time.Time, len(dates))
+	for i, date := range dates {
+		index[i] = keys[date]
+	}
+	rows := make([][]int, len(columns))
+	for j := range columns {
+		rows[j] = make([]int, len(dates))
+		for i, date := range dates {
+			position, ok := positions[j][date]
+			if !ok {
+				position = -1
+			}
+			rows[j][i] = position
+		}
+	}
+	return index, rows, nil
+}
+
+// carryForward returns a bar at date that repeats the close of previous without volume
+func carryForward(previous PriceDataPoint, date int64) PriceDataPoint {
+	return PriceDataPoint{
+		Date:            date,
+		Open:            previous.Close,
+		High:            previous.Close,
+		Low:             previous.Close,
+		Close:           previous.Close,
+		UnadjustedOpen:  previous.UnadjustedClose,
+		UnadjustedHigh:  previous.UnadjustedClose,
+		UnadjustedLow:   previous.UnadjustedClose,
+		UnadjustedClose: previous.UnadjustedClose,
+	}
+}
@@ -0,0 +1,208 @@
+package laplace
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResampleIntraday(t *testing.T) {
+	session := DefaultMarketSession(RegionUs)
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 3, 10, hour, minute, 0, 0, RegionUs.Location())
+	}
+
+	// A minute bar for the whole session plus the closing print at 16:00
+	var points []PriceDataPoint
+	for t := at(9, 30); !t.After(at(16, 0)); t = t.Add(time.Minute) {
+		minutes := float64(t.Sub(at(9, 30)) / time.Minute)
+		points = append(points, PriceDataPoint{
+			Date:            t.UnixMilli(),
+			Open:            minutes,
+			High:            minutes + 1,
+			Low:             minutes - 1,
+			Close:           minutes + 0.5,
+			Volume:          10,
+			UnadjustedClose: 2 * minutes,
+			UnadjustedVol:   20,
+		})
+	}
+
+	hourly, err := ResampleIntraday(points, time.Hour, session)
+	require.NoError(t, err)
+	require.Len(t, hourly, 7)
+
+	require.Equal(t, at(9, 30), hourly[0].ExchangeTime(RegionUs))
+	require.Equal(t, PriceDataPoint{
+		Date:            at(9, 30).UnixMilli(),
+		Open:            0,
+		High:            60,
+		Low:             -1,
+		Close:           59.5,
+		Volume:          600,
+		UnadjustedClose: 118,
+		UnadjustedVol:   1200,
+	}, hourly[0])
+
+	// The last bucket is cut at the close and takes the closing print
+	last := hourly[6]
+	require.Equal(t, at(15, 30), last.ExchangeTime(RegionUs))
+	require.Equal(t, 390.5, last.Close)
+	require.Equal(t, 310.0, last.Volume)
+
+	_, err = ResampleIntraday([]PriceDataPoint{{Date: 2}, {Date: 1}}, time.Hour, session)
+	require.ErrorContains(t, err, "not ordered")
+}
+
+func TestMarketSessionBuckets(t *testing.T) {
+	session := DefaultMarketSession(RegionTr)
+	session.Breaks = []SessionBreak{BISTLunchBreak}
+	session.HalfDays = map[string]time.Duration{"2025-03-28": 12*time.Hour + 30*time.Minute}
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 3, day, hour, minute, 0, 0, RegionTr.Location())
+	}
+
+	for _, test := range []struct {
+		at, want time.Time
+	}{
+		{at(27, 9, 55), at(27, 10, 0)},
+		{at(27, 12, 29), at(27, 12, 0)},
+		{at(27, 13, 15), at(27, 12, 0)},
+		{at(27, 14, 0), at(27, 14, 0)},
+		{at(27, 17, 59), at(27, 17, 0)},
+		{at(27, 18, 5), at(27, 17, 0)},
+		{at(28, 12, 45), at(28, 12, 0)},
+	} {
+		require.Equal(t, test.want, session.bucket(test.at, time.Hour), test.at)
+	}
+
+	require.True(t, session.TradingDay(at(28, 0, 0)))
+	require.False(t, session.TradingDay(at(29, 0, 0)))
+	session.Holidays = map[string]bool{"2025-03-31": true}
+	require.False(t, session.TradingDay(at(31, 12, 0)))
+}
+
+func TestResampleCalendar(t *testing.T) {
+	location := RegionTr.Location()
+	var points []PriceDataPoint
+	for day := 1; day <= 14; day++ {
+		date := time.Date(2025, 1, day, 0, 0, 0, 0, location)
+		points = append(points, PriceDataPoint{Date: date.UnixMilli(), Open: float64(day), High: float64(day), Low: float64(day), Close: float64(day), Volume: 1})
+	}
+
+	weekly, err := ResampleCalendar(points, CalendarWeek, location)
+	require.NoError(t, err)
+	require.Len(t, weekly, 3)
+	require.Equal(t, time.Date(2024, 12, 30, 0, 0, 0, 0, location), weekly[0].ExchangeTime(RegionTr))
+	require.Equal(t, []float64{1, 5, 1, 5, 5}, []float64{weekly[0].Open, weekly[0].Close, weekly[0].Low, weekly[0].High, weekly[0].Volume})
+	require.Equal(t, time.Date(2025, 1, 13, 0, 0, 0, 0, location), weekly[2].ExchangeTime(RegionTr))
+
+	quarterly, err := ResampleCalendar(points, CalendarQuarter, location)
+	require.NoError(t, err)
+	require.Len(t, quarterly, 1)
+	require.Equal(t, 14.0, quarterly[0].Volume)
+
+	_, err = ResampleCalendar(points, "fortnight", location)
+	require.ErrorContains(t, err, "invalid calendar period")
+}
+
+func TestFillSessionGaps(t *testing.T) {
+	session := DefaultMarketSession(RegionUs)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 3, day, hour, minute, 0, 0, RegionUs.Location())
+	}
+
+	// Friday 14:30 to Monday 10:30, with Friday 15:30 missing
+	points := []PriceDataPoint{
+		{Date: at(7, 14, 30).UnixMilli(), Close: 1, UnadjustedClose: 2, Volume: 5},
+		{Date: at(10, 9, 30).UnixMilli(), Close: 3},
+		{Date: at(10, 10, 30).UnixMilli(), Close: 4},
+	}
+
+	filled, err := FillSessionGaps(points, time.Hour, session)
+	require.NoError(t, err)
+	require.Len(t, filled, 4)
+	require.Equal(t, PriceDataPoint{
+		Date:            at(7, 15, 30).UnixMilli(),
+		Open:            1,
+		High:            1,
+		Low:             1,
+		Close:           1,
+		UnadjustedOpen:  2,
+		UnadjustedHigh:  2,
+		UnadjustedLow:   2,
+		UnadjustedClose: 2,
+	}, filled[1])
+	require.Equal(t, points[1], filled[2])
+
+	// Bars dated in seconds are matched with the buckets like bars in milliseconds
+	points[1].Date = at(10, 9, 30).Unix()
+	filled, err = FillSessionGaps(points, time.Hour, session)
+	require.NoError(t, err)
+	require.Len(t, filled, 4)
+	require.Equal(t, points[1], filled[2])
+}
+
+func TestAlignSeries(t *testing.T) {
+	a := []PriceDataPoint{{Date: 1, Close: 10}, {Date: 2, Close: 11}, {Date: 4, Close: 12}}
+	b := []PriceDataPoint{{Date: 2, Close: 20}, {Date: 3, Close: 21}, {Date: 4, Close: 22}}
+
+	union, err := AlignSeries(map[string][]PriceDataPoint{"A": a, "B": b}, AlignFillForward, nil)
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{time.Unix(1, 0).UTC(), time.Unix(2, 0).UTC(), time.Unix(3, 0).UTC(), time.Unix(4, 0).UTC()}, union.Dates)
+	require.Equal(t, []float64{10, 11, 11, 12}, closesOf(union.Series["A"]))
+	require.Equal(t, []float64{0, 20, 21, 22}, closesOf(union.Series["B"]))
+	require.Zero(t, union.Series["A"][2].Volume)
+
+	unfilled, err := AlignSeries(map[string][]PriceDataPoint{"A": a, "B": b}, AlignFillNone, nil)
+	require.NoError(t, err)
+	require.Equal(t, []float64{10, 11, 0, 12}, closesOf(unfilled.Series["A"]))
+	require.Equal(t, PriceDataPoint{Date: 3000}, unfilled.Series["A"][2])
+
+	intersection, err := AlignSeries(map[string][]PriceDataPoint{"A": a, "B": b}, AlignFillDrop, nil)
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{time.Unix(2, 0).UTC(), time.Unix(4, 0).UTC()}, intersection.Dates)
+	require.Equal(t, []float64{20, 22}, closesOf(intersection.Series["B"]))
+}
+
+func TestAlignSeriesAcrossRegions(t *testing.T) {
+	istanbul, newYork := RegionTr.Location(), RegionUs.Location()
+	day := func(d int, location *time.Location, hour, minute int) int64 {
+		return time.Date(2025, 3, d, hour, minute, 0, 0, location).UnixMilli()
+	}
+
+	// Daily bars stamped at midnight in Istanbul and at the open in New York never share a
+	// timestamp. Friday the 7th is a holiday in Istanbul, Monday the 10th in New York.
+	tr := []PriceDataPoint{{Date: day(5, istanbul, 0, 0), Close: 10}, {Date: day(6, istanbul, 0, 0), Close: 11}, {Date: day(10, istanbul, 0, 0), Close: 12}}
+	us := []PriceDataPoint{{Date: day(5, newYork, 9, 30), Close: 20}, {Date: day(6, newYork, 9, 30), Close: 21}, {Date: day(7, newYork, 9, 30), Close: 22}}
+
+	aligned, err := AlignSeries(map[string][]PriceDataPoint{"THYAO": tr, "AAPL": us}, AlignFillForward, istanbul)
+	require.NoError(t, err)
+	var dates []string
+	for _, date := range aligned.Dates {
+		require.Equal(t, istanbul, date.Location())
+		dates = append(dates, date.Format(time.DateTime))
+	}
+	require.Equal(t, []string{"2025-03-05 00:00:00", "2025-03-06 00:00:00", "2025-03-07 00:00:00", "2025-03-10 00:00:00"}, dates)
+	require.Equal(t, []float64{10, 11, 11, 12}, closesOf(aligned.Series["THYAO"]))
+	require.Equal(t, []float64{20, 21, 22, 22}, closesOf(aligned.Series["AAPL"]))
+	require.Equal(t, us[1], aligned.Series["AAPL"][1])
+
+	common, err := AlignSeries(map[string][]PriceDataPoint{"THYAO": tr, "AAPL": us}, AlignFillDrop, istanbul)
+	require.NoError(t, err)
+	require.Len(t, common.Dates, 2)
+
+	// On exact times, nothing lines up
+	exact, err := AlignSeries(map[string][]PriceDataPoint{"THYAO": tr, "AAPL": us}, AlignFillDrop, nil)
+	require.NoError(t, err)
+	require.Empty(t, exact.Dates)
+}
+
+func closesOf(points []PriceDataPoint) []float64 {
+	closes := make([]float64, len(points))
+	for i, point := range points {
+		closes[i] = point.Close
+	}
+	return closes
+}
@@ -0,0 +1,124 @@
+package laplace
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// StreamCodec decodes the payload of server-sent events. It can be replaced with WithStreamCodec
+// to plug in a faster JSON implementation for high-volume streams.
+type StreamCodec interface {
+	Unmarshal(data []byte, v any) error
+}
+
+type jsonStreamCodec struct{}
+
+func (jsonStreamCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// WithStreamCodec configures the client to decode stream events with codec instead of encoding/json.
+func WithStreamCodec(codec StreamCodec) clientOption {
+	return func(c *Client) {
+		c.codec = codec
+	}
+}
+
+// sseReadBufferSize is the size of the reusable read buffer of an SSE connection. Longer lines
+// are still read, through a second buffer that is reused as well.
+const sseReadBufferSize = 64 * 1024
+
+var sseDataPrefix = []byte("data:")
+
+// readSSEData calls onData with the payload of every `data:` line read from r until r is
+// exhausted or onData returns false. The payload is only valid until onData returns, since the
+// read buffers are reused between lines so that reading does not allocate per event.
+func readSSEData(r io.Reader, onData func(data []byte) bool) error {
+	reader := bufio.NewReaderSize(r, sseReadBufferSize)
+
+	var long []byte
+	for {
+		line, err := reader.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			long = append(long[:0], line...)
+			for err == bufio.ErrBufferFull {
+				line, err = reader.ReadSlice('\n')
+				long = append(long, line...)
+			}
+			line = long
+		}
+
+		line = bytes.TrimRight(line, "\r\n")
+		if bytes.HasPrefix(line, sseDataPrefix) {
+			if !onData(line[len(sseDataPrefix):]) {
+				return nil
+			}
+		}
+
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// decodeSSE decodes the payload of every data line read from body into a T and passes it to
+// send until body is exhausted or send returns false. With a pool, events are decoded into
+// pooled objects which consumers hand back through LivePriceResult.Release.
+func decodeSSE[T any](body io.Reader, codec StreamCodec, pool *eventPool[T], send func(LivePriceResult[T]) bool) error {
+	return readSSEData(body, func(data []byte) bool {
+		if pool == nil {
+			var event T
+			if err := codec.Unmarshal(data, &event); err != nil {
+				return send(LivePriceResult[T]{Error: &StreamDecodeError{Err: err}})
+			}
+			return send(LivePriceResult[T]{Data: event})
+		}
+
+		event := pool.get()
+		if err := codec.Unmarshal(data, event); err != nil {
+			pool.put(event)
+			return send(LivePriceResult[T]{Error: &StreamDecodeError{Err: err}})
+		}
+
+		if !send(LivePriceResult[T]{Data: *event, pooled: event, pool: pool}) {
+			pool.put(event)
+			return false
+		}
+		return true
+	})
+}
+
+// resettable is implemented by stream payloads that can be cleared for reuse while keeping
+// their allocated capacity. Pooled payloads that do not implement it are zeroed instead.
+type resettable interface {
+	reset()
+}
+
+// eventPool recycles decoded stream events
+type eventPool[T any] struct {
+	pool sync.Pool
+}
+
+func newEventPool[T any]() *eventPool[T] {
+	return &eventPool[T]{pool: sync.Pool{New: func() any { return new(T) }}}
+}
+
+func (p *eventPool[T]) get() *T {
+	return p.pool.Get().(*T)
+}
+
+func (p *eventPool[T]) put(event *T) {
+	if r, ok := any(event).(resettable); ok {
+		r.reset()
+	} else {
+		var zero T
+		*event = zero
+	}
+	p.pool.Put(event)
+}
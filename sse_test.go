@@ -0,0 +1,144 @@
+package laplace
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSSEData(t *testing.T) {
+	long := strings.Repeat("x", 3*sseReadBufferSize)
+	body := "event: price\r\ndata:{\"a\":1}\r\n\r\n: comment\ndata: {\"a\":2}\ndata:" + long + "\ndata:last"
+
+	var got []string
+	err := readSSEData(strings.NewReader(body), func(data []byte) bool {
+		got = append(got, string(data))
+		return true
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{`{"a":1}`, ` {"a":2}`, long, "last"}, got)
+}
+
+func TestReadSSEDataStops(t *testing.T) {
+	calls := 0
+	err := readSSEData(strings.NewReader("data:1\ndata:2\n"), func(data []byte) bool {
+		calls++
+		return false
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+}
+
+func TestDecodeSSEPooledOrderBook(t *testing.T) {
+	body := `data:{"s":"AKBNK","updated":[{"level":1,"side":"bid","vol":10,"orders":2,"p":5}]}` + "\n" +
+		`data:{"s":"GARAN","updated":[{"level":2,"p":6}]}` + "\n" +
+		`data:not json` + "\n"
+
+	pool := newEventPool[BISTStockOrderBookData]()
+	var results []LivePriceResult[BISTStockOrderBookData]
+	err := decodeSSE(strings.NewReader(body), jsonStreamCodec{}, pool, func(r LivePriceResult[BISTStockOrderBookData]) bool {
+		results = append(results, r)
+		if r.Error == nil && r.Data.Symbol == "AKBNK" {
+			r.Release()
+		}
+		return true
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	require.Equal(t, "GARAN", results[1].Data.Symbol)
+	require.Equal(t, []OrderbookLevel{{ID: 2, Price: 6}}, results[1].Data.Updated)
+	require.Error(t, results[2].Error)
+}
+
+type countingCodec struct {
+	calls int
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v any) error {
+	c.calls++
+	return json.Unmarshal(data, v)
+}
+
+func TestWithStreamCodec(t *testing.T) {
+	codec := &countingCodec{}
+	client, err := NewClient(LaplaceConfiguration{APIKey: "test"}, WithStreamCodec(codec))
+	require.NoError(t, err)
+
+	err = decodeSSE(strings.NewReader("data:{}\n"), client.codec, nil, func(LivePriceResult[BISTBidAskResponse]) bool {
+		return true
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, codec.calls)
+}
+
+// legacyDecodeSSE is the line-scanner decoder streams used before the reusable-buffer reader,
+// kept to benchmark against.
+func legacyDecodeSSE[T any](body *bytes.Reader, send func(LivePriceResult[T]) bool) {
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "data:") {
+			data := strings.TrimPrefix(line, "data:")
+			var event T
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				send(LivePriceResult[T]{Error: fmt.Errorf("error unmarshalling event: %w", err)})
+				continue
+			}
+			send(LivePriceResult[T]{Data: event})
+		}
+	}
+}
+
+const benchmarkSSEEvents = 1000
+
+func benchmarkBidAskBody() []byte {
+	var buf bytes.Buffer
+	for i := 0; i < benchmarkSSEEvents; i++ {
+		fmt.Fprintf(&buf, "data:{\"d\":{\"s\":\"SYM%d\",\"ask\":45.6,\"bid\":45.5,\"d\":1740414373252},\"t\":\"pr\"}\n\n", i%500)
+	}
+	return buf.Bytes()
+}
+
+func BenchmarkDecodeSSELegacy(b *testing.B) {
+	body := benchmarkBidAskBody()
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		legacyDecodeSSE(bytes.NewReader(body), func(r LivePriceResult[BISTBidAskResponse]) bool {
+			return true
+		})
+	}
+}
+
+func BenchmarkDecodeSSE(b *testing.B) {
+	body := benchmarkBidAskBody()
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		decodeSSE(bytes.NewReader(body), jsonStreamCodec{}, nil, func(r LivePriceResult[BISTBidAskResponse]) bool {
+			return true
+		})
+	}
+}
+
+func BenchmarkDecodeSSEPooled(b *testing.B) {
+	body := benchmarkBidAskBody()
+	pool := newEventPool[BISTBidAskResponse]()
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		decodeSSE(bytes.NewReader(body), jsonStreamCodec{}, pool, func(r LivePriceResult[BISTBidAskResponse]) bool {
+			r.Release()
+			return true
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package laplace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	// Chart images come as PNG, JPEG or GIF depending on the server
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// ChartIndicator is an indicator the chart endpoint draws on a chart.
+type ChartIndicator string
+
+const (
+	ChartIndicatorSMA            ChartIndicator = "SMA"
+	ChartIndicatorEMA            ChartIndicator = "EMA"
+	ChartIndicatorRSI            ChartIndicator = "RSI"
+	ChartIndicatorMACD           ChartIndicator = "MACD"
+	ChartIndicatorBollingerBands ChartIndicator = "BB"
+	ChartIndicatorVolume         ChartIndicator = "VOLUME"
+)
+
+func (i ChartIndicator) valid() bool {
+	switch i {
+	case ChartIndicatorSMA, ChartIndicatorEMA, ChartIndicatorRSI, ChartIndicatorMACD, ChartIndicatorBollingerBands, ChartIndicatorVolume:
+		return true
+	}
+	return false
+}
+
+// ChartType is the way the chart endpoint draws prices. The zero value leaves the choice to
+// the server.
+type ChartType int
+
+const (
+	ChartTypeDefault ChartType = iota
+	ChartTypeBars
+	ChartTypeCandles
+	ChartTypeLine
+	ChartTypeArea
+)
+
+// chartTypeCodes are the values of the chartType query parameter
+var chartTypeCodes = map[ChartType]int{
+	ChartTypeBars:    0,
+	ChartTypeCandles: 1,
+	ChartTypeLine:    2,
+	ChartTypeArea:    3,
+}
+
+func (t ChartType) String() string {
+	switch t {
+	case ChartTypeDefault:
+		return "default"
+	case ChartTypeBars:
+		return "bars"
+	case ChartTypeCandles:
+		return "candles"
+	case ChartTypeLine:
+		return "line"
+	case ChartTypeArea:
+		return "area"
+	}
+	return fmt.Sprintf("ChartType(%d)", int(t))
+}
+
+type GenerateChartImageRequest struct {
+	Symbol     string
+	Period     HistoricalPricePeriod
+	Region     Region
+	Resolution HistoricalPriceInterval
+	Indicators []ChartIndicator
+	ChartType  ChartType
+}
+
+// Validate checks the request before it is sent, reporting every problem found.
+func (r GenerateChartImageRequest) Validate() error {
+	var errs []error
+	if r.Symbol == "" {
+		errs = append(errs, errors.New("symbol is required"))
+	}
+	if r.Region != RegionTr && r.Region != RegionUs {
+		errs = append(errs, fmt.Errorf("invalid region %q", r.Region))
+	}
+	if r.Period != "" && !validHistoricalPricePeriod(r.Period) {
+		errs = append(errs, fmt.Errorf("invalid period %q", r.Period))
+	}
+	if r.Resolution != "" && r.Resolution.Duration() == 0 {
+		errs = append(errs, fmt.Errorf("invalid resolution %q", r.Resolution))
+	}
+
+	seen := make(map[ChartIndicator]bool, len(r.Indicators))
+	for _, indicator := range r.Indicators {
+		switch {
+		case !indicator.valid():
+			errs = append(errs, fmt.Errorf("invalid chart indicator %q", indicator))
+		case seen[indicator]:
+			errs = append(errs, fmt.Errorf("duplicate chart indicator %q", indicator))
+		}
+		seen[indicator] = true
+	}
+
+	if _, ok := chartTypeCodes[r.ChartType]; !ok && r.ChartType != ChartTypeDefault {
+		errs = append(errs, fmt.Errorf("invalid chart type %s", r.ChartType))
+	}
+	return errors.Join(errs...)
+}
+
+func validHistoricalPricePeriod(period HistoricalPricePeriod) bool {
+	switch period {
+	case HistoricalPricePeriodOneDay, HistoricalPricePeriodOneWeek, HistoricalPricePeriodOneMonth,
+		HistoricalPricePeriodThreeMonth, HistoricalPricePeriodSixMonth, HistoricalPricePeriodOneYear,
+		HistoricalPricePeriodTwoYear, HistoricalPricePeriodThreeYear, HistoricalPricePeriodFiveYear,
+		HistoricalPricePeriodAll:
+		return true
+	}
+	return false
+}
+
+// ChartImage is a chart generated by the chart endpoint.
+type ChartImage struct {
+	Data []byte
+	// ContentType is detected from Data, such as image/png
+	ContentType string
+}
+
+// Decode decodes a PNG, JPEG or GIF chart image.
+func (i *ChartImage) Decode() (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(i.Data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s chart image: %w", i.ContentType, err)
+	}
+	return img, nil
+}
+
+// Extension returns the file name extension of the image format, such as ".png".
+func (i *ChartImage) Extension() string {
+	switch i.ContentType {
+	case "image/png":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "image/svg+xml":
+		return ".svg"
+	}
+	return ".bin"
+}
+
+// detectChartContentType sniffs the format of a chart image. SVG is recognized as well, which
+// http.DetectContentType reports as XML or text.
+func detectChartContentType(data []byte) string {
+	head := bytes.TrimSpace(data[:min(len(data), 512)])
+	if bytes.HasPrefix(head, []byte("<svg")) || (bytes.HasPrefix(head, []byte("<?xml")) && bytes.Contains(head, []byte("<svg"))) {
+		return "image/svg+xml"
+	}
+	contentType, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	return contentType
+}
+
+// GetStockChartImage generates a chart image for a stock.
+func (c *Client) GetStockChartImage(ctx context.Context, params GenerateChartImageRequest) (*ChartImage, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/stock/chart", c.baseUrl), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	q.Add("symbol", params.Symbol)
+	q.Add("region", string(params.Region))
+	if params.Period != "" {
+		q.Add("period", string(params.Period))
+	}
+	if params.Resolution != "" {
+		q.Add("resolution", string(params.Resolution))
+	}
+	if len(params.Indicators) > 0 {
+		indicators := make([]string, len(params.Indicators))
+		for i, indicator := range params.Indicators {
+			indicators[i] = string(indicator)
+		}
+		q.Add("indicators", strings.Join(indicators, ","))
+	}
+	if code, ok := chartTypeCodes[params.ChartType]; ok {
+		q.Add("chartType", strconv.Itoa(code))
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := sendRawRequest(ctx, c, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChartImage{Data: resp, ContentType: detectChartContentType(resp)}, nil
+}
+
+// ChartImageFile is the outcome of saving the chart of one symbol.
+type ChartImageFile struct {
+	Symbol string
+	// Path is where the chart was written, empty if it failed
+	Path        string
+	ContentType string
+	Err         error
+}
+
+// SaveWatchlistCharts generates the chart of every symbol with the options of request and
+// writes it to dir as SYMBOL.png (or the extension of its format), concurrency charts at a time
+// (4 by default). A failing symbol does not stop the others: the results, in the order of
+// symbols, report what happened to each. The returned error is only set when dir cannot be
+// created, the options are invalid or ctx is done.
+func (c *Client) SaveWatchlistCharts(ctx context.Context, dir string, symbols []string, request GenerateChartImageRequest, concurrency int) ([]ChartImageFile, error) {
+	if len(symbols) > 0 {
+		request.Symbol = symbols[0]
+		if err := request.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create chart directory: %w", err)
+	}
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	results := make([]ChartImageFile, len(symbols))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, symbol := range symbols {
+		wg.Add(1)
+		go func(i int, symbol string) {
+			defer wg.Done()
+			results[i] = ChartImageFile{Symbol: symbol}
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i].Err = ctx.Err()
+				return
+			}
+
+			request := request
+			request.Symbol = symbol
+			chart, err := c.GetStockChartImage(ctx, request)
+			if err != nil {
+				results[i].Err = err
+				return
+			}
+
+			name := strings.NewReplacer("/", "_", `\`, "_").Replace(strings.ToUpper(symbol))
+			path := filepath.Join(dir, name+chart.Extension())
+			if err := os.WriteFile(path, chart.Data, 0o644); err != nil {
+				results[i].Err = err
+				return
+			}
+			results[i].Path, results[i].ContentType = path, chart.ContentType
+		}(i, symbol)
+	}
+	wg.Wait()
+
+	return results, ctx.Err()
+}
@@ -0,0 +1,158 @@
+package laplace
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(1, 1, color.NRGBA{0xFF, 0, 0, 0xFF})
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestGetStockChartImageOffline(t *testing.T) {
+	pngData := testPNG(t)
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/stock/chart", r.URL.Path)
+		query = r.URL.Query()
+		w.Write(pngData)
+	}))
+	defer srv.Close()
+
+	client := newOfflineTestClient(t, srv.URL)
+	chart, err := client.GetStockChartImage(context.Background(), GenerateChartImageRequest{
+		Symbol:     "THYAO",
+		Region:     RegionTr,
+		Period:     HistoricalPricePeriodThreeMonth,
+		Resolution: HistoricalPriceIntervalOneDay,
+		Indicators: []ChartIndicator{ChartIndicatorSMA, ChartIndicatorBollingerBands},
+		ChartType:  ChartTypeCandles,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "THYAO", query.Get("symbol"))
+	require.Equal(t, "3M", query.Get("period"))
+	require.Equal(t, "1d", query.Get("resolution"))
+	require.Equal(t, "SMA,BB", query.Get("indicators"))
+	require.Equal(t, "1", query.Get("chartType"))
+
+	require.Equal(t, "image/png", chart.ContentType)
+	require.Equal(t, ".png", chart.Extension())
+	img, err := chart.Decode()
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 3, 2), img.Bounds())
+
+	// The server picks the chart type when none is set
+	_, err = client.GetStockChartImage(context.Background(), GenerateChartImageRequest{Symbol: "THYAO", Region: RegionTr})
+	require.NoError(t, err)
+	require.False(t, query.Has("chartType"))
+	require.False(t, query.Has("indicators"))
+}
+
+func TestGenerateChartImageRequestValidate(t *testing.T) {
+	require.NoError(t, GenerateChartImageRequest{Symbol: "AAPL", Region: RegionUs, ChartType: ChartTypeArea}.Validate())
+
+	err := GenerateChartImageRequest{
+		Region:     "de",
+		Period:     "2W",
+		Resolution: "4h",
+		Indicators: []ChartIndicator{ChartIndicatorRSI, "ICHIMOKU", ChartIndicatorRSI},
+		ChartType:  ChartType(9),
+	}.Validate()
+	require.Error(t, err)
+	for _, problem := range []string{
+		"symbol is required",
+		`invalid region "de"`,
+		`invalid period "2W"`,
+		`invalid resolution "4h"`,
+		`invalid chart indicator "ICHIMOKU"`,
+		`duplicate chart indicator "RSI"`,
+		"invalid chart type ChartType(9)",
+	} {
+		require.ErrorContains(t, err, problem)
+	}
+
+	// Invalid requests are not sent
+	client := newOfflineTestClient(t, "http://127.0.0.1:0")
+	_, err = client.GetStockChartImage(context.Background(), GenerateChartImageRequest{Symbol: "THYAO", Region: RegionTr, Indicators: []ChartIndicator{"sma"}})
+	require.ErrorContains(t, err, `invalid chart indicator "sma"`)
+}
+
+func TestDetectChartContentType(t *testing.T) {
+	require.Equal(t, "image/png", detectChartContentType(testPNG(t)))
+	require.Equal(t, "image/jpeg", detectChartContentType([]byte("\xFF\xD8\xFF\xE0rest")))
+	require.Equal(t, "image/svg+xml", detectChartContentType([]byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`)))
+	require.Equal(t, "image/svg+xml", detectChartContentType([]byte("  <svg></svg>")))
+	require.Equal(t, "text/plain", detectChartContentType([]byte("not an image")))
+
+	chart := &ChartImage{Data: []byte("<svg></svg>"), ContentType: "image/svg+xml"}
+	require.Equal(t, ".svg", chart.Extension())
+	_, err := chart.Decode()
+	require.ErrorContains(t, err, "failed to decode image/svg+xml chart image")
+}
+
+func TestSaveWatchlistCharts(t *testing.T) {
+	pngData := testPNG(t)
+	var (
+		mu        sync.Mutex
+		requested []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		symbol := r.URL.Query().Get("symbol")
+		mu.Lock()
+		requested = append(requested, symbol)
+		mu.Unlock()
+
+		require.Equal(t, "RSI", r.URL.Query().Get("indicators"))
+		if symbol == "MISSING" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"stock not found"}`))
+			return
+		}
+		w.Write(pngData)
+	}))
+	defer srv.Close()
+
+	dir := filepath.Join(t.TempDir(), "charts")
+	client := newOfflineTestClient(t, srv.URL)
+	results, err := client.SaveWatchlistCharts(context.Background(), dir, []string{"thyao", "MISSING", "GARAN"}, GenerateChartImageRequest{
+		Region:     RegionTr,
+		Indicators: []ChartIndicator{ChartIndicatorRSI},
+	}, 2)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.ElementsMatch(t, []string{"thyao", "MISSING", "GARAN"}, requested)
+
+	require.Equal(t, "thyao", results[0].Symbol)
+	require.NoError(t, results[0].Err)
+	require.Equal(t, filepath.Join(dir, "THYAO.png"), results[0].Path)
+	require.Equal(t, "image/png", results[0].ContentType)
+	data, err := os.ReadFile(results[0].Path)
+	require.NoError(t, err)
+	require.Equal(t, pngData, data)
+
+	require.Error(t, results[1].Err)
+	require.Empty(t, results[1].Path)
+	require.NoError(t, results[2].Err)
+
+	_, err = client.SaveWatchlistCharts(context.Background(), dir, []string{"THYAO"}, GenerateChartImageRequest{Region: "de"}, 0)
+	require.ErrorContains(t, err, "invalid region")
+}
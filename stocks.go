@@ -112,6 +112,35 @@ const (
 	HistoricalPriceIntervalThirtyDay     HistoricalPriceInterval = "30d"
 )
 
+// Duration returns the length of a bar of the interval, or 0 for an unknown interval.
+func (i HistoricalPriceInterval) Duration() time.Duration {
+	switch i {
+	case HistoricalPriceIntervalOneMinute:
+		return time.Minute
+	case HistoricalPriceIntervalThreeMinute:
+		return 3 * time.Minute
+	case HistoricalPriceIntervalFiveMinute:
+		return 5 * time.Minute
+	case HistoricalPriceIntervalFifteenMinute:
+		return 15 * time.Minute
+	case HistoricalPriceIntervalThirtyMinute:
+		return 30 * time.Minute
+	case HistoricalPriceIntervalOneHour:
+		return time.Hour
+	case HistoricalPriceIntervalTwoHour:
+		return 2 * time.Hour
+	case HistoricalPriceIntervalOneDay:
+		return 24 * time.Hour
+	case HistoricalPriceIntervalFiveDay:
+		return 5 * 24 * time.Hour
+	case HistoricalPriceIntervalSevenDay:
+		return 7 * 24 * time.Hour
+	case HistoricalPriceIntervalThirtyDay:
+		return 30 * 24 * time.Hour
+	}
+	return 0
+}
+
 type HistoricalPriceDate struct {
 	Year   int
 	Month  int
@@ -133,6 +162,30 @@ type StockPriceGraph struct {
 	FiveYear   []PriceDataPoint `json:"5Y"`
 }
 
+// Period returns the prices of the graph for a period key, as requested from
+// GetHistoricalPrices. It reports false for periods the graph has no field for.
+func (g StockPriceGraph) Period(period HistoricalPricePeriod) ([]PriceDataPoint, bool) {
+	switch period {
+	case HistoricalPricePeriodOneDay:
+		return g.OneDay, true
+	case HistoricalPricePeriodOneWeek:
+		return g.OneWeek, true
+	case HistoricalPricePeriodOneMonth:
+		return g.OneMonth, true
+	case HistoricalPricePeriodThreeMonth:
+		return g.ThreeMonth, true
+	case HistoricalPricePeriodOneYear:
+		return g.OneYear, true
+	case HistoricalPricePeriodTwoYear:
+		return g.TwoYear, true
+	case HistoricalPricePeriodThreeYear:
+		return g.ThreeYear, true
+	case HistoricalPricePeriodFiveYear:
+		return g.FiveYear, true
+	}
+	return nil, false
+}
+
 type PriceDataPoint struct {
 	Date            int64   `json:"d"`
 	Open            float64 `json:"o"`
@@ -357,44 +410,3 @@ func (c *Client) GetTickRules(ctx context.Context, symbol string, region Region)
 
 	return resp, nil
 }
-
-type GenerateChartImageRequest struct {
-	Symbol     string
-	Period     HistoricalPricePeriod
-	Region     Region
-	Resolution HistoricalPriceInterval
-	Indicators []string
-	ChartType  *int
-}
-
-// GetStockChartImage generates a chart image for a stock and returns the raw image bytes.
-func (c *Client) GetStockChartImage(ctx context.Context, params GenerateChartImageRequest) ([]byte, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/stock/chart", c.baseUrl), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	q := req.URL.Query()
-	q.Add("symbol", params.Symbol)
-	q.Add("region", string(params.Region))
-	if params.Period != "" {
-		q.Add("period", string(params.Period))
-	}
-	if params.Resolution != "" {
-		q.Add("resolution", string(params.Resolution))
-	}
-	if len(params.Indicators) > 0 {
-		q.Add("indicators", strings.Join(params.Indicators, ","))
-	}
-	if params.ChartType != nil {
-		q.Add("chartType", strconv.Itoa(*params.ChartType))
-	}
-	req.URL.RawQuery = q.Encode()
-
-	resp, err := sendRawRequest(ctx, c, req)
-	if err != nil {
-		return nil, err
-	}
-
-	return resp, nil
-}
@@ -2,6 +2,7 @@ package laplace
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -315,5 +316,23 @@ func (s *StocksTestSuite) TestGetStockChartImage() {
 	})
 	s.Require().NoError(err)
 	s.Require().NotNil(resp)
-	s.Require().Greater(len(resp), 0)
+	s.Require().Greater(len(resp.Data), 0)
+	s.Require().True(strings.HasPrefix(resp.ContentType, "image/"), resp.ContentType)
+
+	if resp.ContentType != "image/svg+xml" && resp.ContentType != "image/webp" {
+		img, err := resp.Decode()
+		s.Require().NoError(err)
+		s.Require().Greater(img.Bounds().Dx(), 0)
+	}
+
+	resp, err = client.GetStockChartImage(ctx, GenerateChartImageRequest{
+		Symbol:     "TUPRS",
+		Region:     RegionTr,
+		Period:     HistoricalPricePeriodOneMonth,
+		Resolution: HistoricalPriceIntervalOneDay,
+		Indicators: []ChartIndicator{ChartIndicatorSMA, ChartIndicatorRSI},
+		ChartType:  ChartTypeCandles,
+	})
+	s.Require().NoError(err)
+	s.Require().Greater(len(resp.Data), 0)
 }
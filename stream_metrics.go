@@ -0,0 +1,169 @@
+package laplace
+
+import (
+	"sync"
+	"time"
+)
+
+// StreamMetrics receives telemetry from live price and news streams. Implementations adapt it
+// to Prometheus or any other metrics sink, e.g. IncEvents to a counter and ObserveLatency to a
+// histogram. Every method receives the name of the stream, such as "price:tr" or "news:us", and
+// is called from stream goroutines, so implementations must be safe for concurrent use.
+type StreamMetrics interface {
+	// IncEvents counts a decoded event.
+	IncEvents(stream string)
+	// ObserveLatency records the delay between an event's own timestamp and its receive time.
+	ObserveLatency(stream string, latency time.Duration)
+	// IncDecodeErrors counts an event that could not be decoded.
+	IncDecodeErrors(stream string)
+	// IncReconnects counts a connection re-established after the initial subscription.
+	IncReconnects(stream string)
+	// IncDropped counts an event that was received but never delivered to the consumer.
+	IncDropped(stream string)
+}
+
+// WithStreamMetrics configures the client to report telemetry of every stream it creates.
+func WithStreamMetrics(metrics StreamMetrics) clientOption {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// StreamStatsSnapshot is a point-in-time view of a single stream's telemetry.
+type StreamStatsSnapshot struct {
+	Events          int64
+	EventsPerSecond float64
+	DecodeErrors    int64
+	Reconnects      int64
+	Dropped         int64
+	LastLatency     time.Duration
+	AverageLatency  time.Duration
+	MaxLatency      time.Duration
+}
+
+// StreamStats is an in-memory StreamMetrics implementation for processes that do not export
+// metrics elsewhere. Events per second are computed over a sliding window.
+type StreamStats struct {
+	mu      sync.Mutex
+	window  time.Duration
+	now     func() time.Time
+	streams map[string]*streamStats
+}
+
+type streamStats struct {
+	snapshot     StreamStatsSnapshot
+	totalLatency time.Duration
+	latencies    int64
+	// buckets counts events per second over the window, indexed by unix second modulo its length
+	buckets     []int64
+	bucketTimes []int64
+}
+
+// NewStreamStats creates a StreamStats computing event rates over the given window, which
+// defaults to one minute.
+func NewStreamStats(window time.Duration) *StreamStats {
+	if window < time.Second {
+		window = time.Minute
+	}
+
+	return &StreamStats{
+		window:  window,
+		now:     time.Now,
+		streams: make(map[string]*streamStats),
+	}
+}
+
+func (s *StreamStats) get(stream string) *streamStats {
+	st, ok := s.streams[stream]
+	if !ok {
+		size := int(s.window / time.Second)
+		st = &streamStats{buckets: make([]int64, size), bucketTimes: make([]int64, size)}
+		s.streams[stream] = st
+	}
+	return st
+}
+
+func (s *StreamStats) IncEvents(stream string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.get(stream)
+	st.snapshot.Events++
+
+	second := s.now().Unix()
+	i := int(second % int64(len(st.buckets)))
+	if st.bucketTimes[i] != second {
+		st.bucketTimes[i] = second
+		st.buckets[i] = 0
+	}
+	st.buckets[i]++
+}
+
+func (s *StreamStats) ObserveLatency(stream string, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.get(stream)
+	st.snapshot.LastLatency = latency
+	st.snapshot.MaxLatency = max(st.snapshot.MaxLatency, latency)
+	st.totalLatency += latency
+	st.latencies++
+}
+
+func (s *StreamStats) IncDecodeErrors(stream string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.get(stream).snapshot.DecodeErrors++
+}
+
+func (s *StreamStats) IncReconnects(stream string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.get(stream).snapshot.Reconnects++
+}
+
+func (s *StreamStats) IncDropped(stream string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.get(stream).snapshot.Dropped++
+}
+
+// Snapshot returns the current telemetry of every stream that reported any, keyed by stream name.
+func (s *StreamStats) Snapshot() map[string]StreamStatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := s.now().Unix() - int64(s.window/time.Second)
+	snapshots := make(map[string]StreamStatsSnapshot, len(s.streams))
+	for name, st := range s.streams {
+		snapshot := st.snapshot
+		if st.latencies > 0 {
+			snapshot.AverageLatency = st.totalLatency / time.Duration(st.latencies)
+		}
+
+		var events int64
+		for i, second := range st.bucketTimes {
+			if second > cutoff {
+				events += st.buckets[i]
+			}
+		}
+		snapshot.EventsPerSecond = float64(events) / s.window.Seconds()
+
+		snapshots[name] = snapshot
+	}
+
+	return snapshots
+}
+
+// liveStreamName is the name live price streams report telemetry under
+func liveStreamName(priceType LivePriceType, region Region) string {
+	return string(priceType) + ":" + string(region)
+}
+
+// newsStreamName is the name news streams report telemetry under
+func newsStreamName(region Region) string {
+	return "news:" + string(region)
+}
@@ -0,0 +1,73 @@
+package laplace
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamStatsSnapshot(t *testing.T) {
+	stats := NewStreamStats(10 * time.Second)
+	now := time.Unix(1_700_000_000, 0)
+	stats.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		stats.IncEvents("price:tr")
+	}
+	now = now.Add(20 * time.Second)
+	for i := 0; i < 10; i++ {
+		stats.IncEvents("price:tr")
+	}
+
+	stats.ObserveLatency("price:tr", 100*time.Millisecond)
+	stats.ObserveLatency("price:tr", 300*time.Millisecond)
+	stats.ObserveLatency("price:tr", 200*time.Millisecond)
+	stats.IncDecodeErrors("price:tr")
+	stats.IncReconnects("price:tr")
+	stats.IncDropped("news:tr")
+
+	snapshot := stats.Snapshot()
+	require.Equal(t, StreamStatsSnapshot{
+		Events:          15,
+		EventsPerSecond: 1,
+		DecodeErrors:    1,
+		Reconnects:      1,
+		LastLatency:     200 * time.Millisecond,
+		AverageLatency:  200 * time.Millisecond,
+		MaxLatency:      300 * time.Millisecond,
+	}, snapshot["price:tr"])
+	require.Equal(t, int64(1), snapshot["news:tr"].Dropped)
+}
+
+func TestLivePriceStreamMetrics(t *testing.T) {
+	srv, lines := newSSETestServer(t)
+
+	stats := NewStreamStats(time.Minute)
+	client, err := NewClient(LaplaceConfiguration{APIKey: "test", BaseURL: srv.URL}, WithStreamMetrics(stats))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := client.GetLivePriceStreamForBIST()
+	require.NoError(t, stream.Subscribe(ctx, []string{"AKBNK"}))
+	defer stream.Close()
+
+	sent := time.Now().Add(-time.Second)
+	lines <- fmt.Sprintf(`{"symbol":"AKBNK","type":"pr","data":{"s":"AKBNK","p":1,"d":%d}}`, sent.UnixMilli())
+	lines <- `not json`
+	lines <- fmt.Sprintf(`{"symbol":"AKBNK","type":"pr","data":{"s":"AKBNK","p":2,"d":%d}}`, sent.UnixMilli())
+
+	for i := 0; i < 3; i++ {
+		<-stream.Receive()
+	}
+
+	snapshot := stats.Snapshot()[liveStreamName(LivePriceTypePrice, RegionTr)]
+	require.Equal(t, int64(2), snapshot.Events)
+	require.Equal(t, int64(1), snapshot.DecodeErrors)
+	require.GreaterOrEqual(t, snapshot.MaxLatency, time.Second)
+	require.Less(t, snapshot.MaxLatency, 5*time.Second)
+}
@@ -0,0 +1,168 @@
+package laplace
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// Run calls handler for every event of a subscribed stream and blocks until ctx ends, the
+// stream is closed, resubscribed or its subscription context ends, handler returns an error or
+// a terminal error arrives. Recoverable decode errors are passed to handler with a zero value
+// and the stream carries on; terminal transport errors are returned, as are handler errors and
+// ctx.Err(). A transport error is a *LiveShardError naming the symbols of the failed
+// connection; the other connections of a sharded stream carry on, and Run can be called again
+// to keep consuming them. Pooled events are released once handler returns.
+func (s *LivePriceStream[T]) Run(ctx context.Context, handler func(data T, err error) error) error {
+	var handlerErr error
+	err := s.consume(ctx.Done(), func(data T, err error) bool {
+		handlerErr = handler(data, err)
+		return handlerErr == nil
+	})
+
+	switch {
+	case handlerErr != nil:
+		return handlerErr
+	case err != nil:
+		return err
+	default:
+		return ctx.Err()
+	}
+}
+
+// All returns an iterator over the events of a subscribed stream, to be used as
+//
+//	for data, err := range stream.All() { ... }
+//
+// Recoverable decode errors are yielded with a zero value and iteration continues; a terminal
+// error, a *LiveShardError as in Run, is yielded last. Iteration also ends when the stream is
+// closed, resubscribed or its subscription context ends. Pooled events are released when the
+// loop body returns.
+func (s *LivePriceStream[T]) All() iter.Seq2[T, error] {
+	return func(yield func(data T, err error) bool) {
+		if err := s.consume(nil, yield); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// consume delivers the stream's events to yield until done is closed, the stream ends or yield
+// returns false, and returns the terminal error that ended the stream, if any
+func (s *LivePriceStream[T]) consume(done <-chan struct{}, yield func(data T, err error) bool) error {
+	s.mu.RLock()
+	subscribed, results, streamCtx := s.isSubscribed, s.outputChan, s.ctx
+	s.mu.RUnlock()
+
+	if !subscribed {
+		return fmt.Errorf("stream is not subscribed")
+	}
+
+	for {
+		select {
+		case <-done:
+			return nil
+		case <-streamCtx.Done():
+			return nil
+		case result, ok := <-results:
+			if !ok {
+				return nil
+			}
+
+			if result.Error != nil {
+				if !IsRecoverable(result.Error) {
+					return result.Error
+				}
+				var zero T
+				if !yield(zero, result.Error) {
+					return nil
+				}
+				continue
+			}
+
+			next := yield(result.Data, nil)
+			result.Release()
+			if !next {
+				return nil
+			}
+		}
+	}
+}
+
+// Run calls handler for every news item of a subscribed stream and blocks until ctx ends, the
+// stream is closed, resubscribed or its subscription context ends, handler returns an error or
+// a terminal error arrives. Recoverable decode errors are passed to handler with a zero value
+// and the stream carries on; terminal transport errors are returned, as are handler errors and
+// ctx.Err().
+func (s *NewsStream) Run(ctx context.Context, handler func(news NewsV2, err error) error) error {
+	var handlerErr error
+	err := s.consume(ctx.Done(), func(news NewsV2, err error) bool {
+		handlerErr = handler(news, err)
+		return handlerErr == nil
+	})
+
+	switch {
+	case handlerErr != nil:
+		return handlerErr
+	case err != nil:
+		return err
+	default:
+		return ctx.Err()
+	}
+}
+
+// All returns an iterator over the news items of a subscribed stream, to be used as
+//
+//	for news, err := range stream.All() { ... }
+//
+// Recoverable decode errors are yielded with a zero value and iteration continues; a terminal
+// error is yielded last. Iteration also ends when the stream is closed, resubscribed or its
+// subscription context ends.
+func (s *NewsStream) All() iter.Seq2[NewsV2, error] {
+	return func(yield func(news NewsV2, err error) bool) {
+		if err := s.consume(nil, yield); err != nil {
+			yield(NewsV2{}, err)
+		}
+	}
+}
+
+// consume delivers the stream's news items to yield until done is closed, the stream ends or
+// yield returns false, and returns the terminal error that ended the stream, if any
+func (s *NewsStream) consume(done <-chan struct{}, yield func(news NewsV2, err error) bool) error {
+	s.mu.RLock()
+	subscribed, results, streamCtx := s.isSubscribed, s.outputChan, s.ctx
+	s.mu.RUnlock()
+
+	if !subscribed {
+		return fmt.Errorf("stream is not subscribed")
+	}
+
+	for {
+		select {
+		case <-done:
+			return nil
+		case <-streamCtx.Done():
+			return nil
+		case result, ok := <-results:
+			if !ok {
+				return nil
+			}
+
+			if result.Error != nil {
+				if !IsRecoverable(result.Error) {
+					return result.Error
+				}
+				if !yield(NewsV2{}, result.Error) {
+					return nil
+				}
+				continue
+			}
+
+			for _, news := range result.Data {
+				if !yield(news, nil) {
+					return nil
+				}
+			}
+		}
+	}
+}
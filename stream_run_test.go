@@ -0,0 +1,184 @@
+package laplace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newFiniteSSEServer serves the given data lines on every connection and then closes it
+func newFiniteSSEServer(t *testing.T, lines ...string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		for _, line := range lines {
+			fmt.Fprintf(w, "data:%s\n\n", line)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestLivePriceStreamRun(t *testing.T) {
+	srv := newFiniteSSEServer(t, `{"s":"AAPL","p":1}`, `not json`, `{"s":"MSFT","p":2}`)
+	client := newOfflineTestClient(t, srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := client.GetLivePriceStreamForUS()
+	require.NoError(t, stream.Subscribe(ctx, []string{"AAPL", "MSFT"}))
+	defer stream.Close()
+
+	var symbols []string
+	var decodeErrors int
+	err := stream.Run(ctx, func(data USStockLiveData, err error) error {
+		if err != nil {
+			require.True(t, IsRecoverable(err))
+			decodeErrors++
+			return nil
+		}
+		symbols = append(symbols, data.Symbol)
+		return nil
+	})
+
+	require.ErrorIs(t, err, ErrStreamEnded)
+	require.False(t, IsRecoverable(err))
+	require.Equal(t, []string{"AAPL", "MSFT"}, symbols)
+	require.Equal(t, 1, decodeErrors)
+}
+
+func TestLivePriceStreamRunStops(t *testing.T) {
+	srv, lines := newSSETestServer(t)
+	client := newOfflineTestClient(t, srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := client.GetLivePriceStreamForUS()
+	require.NoError(t, stream.Subscribe(ctx, []string{"AAPL"}))
+	defer stream.Close()
+
+	lines <- `{"s":"AAPL","p":1}`
+	errStop := errors.New("stop")
+	err := stream.Run(ctx, func(data USStockLiveData, err error) error {
+		return errStop
+	})
+	require.ErrorIs(t, err, errStop)
+
+	runCtx, runCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer runCancel()
+	err = stream.Run(runCtx, func(data USStockLiveData, err error) error {
+		return nil
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	err = client.GetLivePriceStreamForUS().Run(ctx, func(data USStockLiveData, err error) error {
+		return nil
+	})
+	require.Error(t, err)
+}
+
+func TestLivePriceStreamAll(t *testing.T) {
+	srv := newFiniteSSEServer(t, `{"s":"AAPL","p":1}`, `not json`, `{"s":"MSFT","p":2}`)
+	client := newOfflineTestClient(t, srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := client.GetLivePriceStreamForUS()
+	require.NoError(t, stream.Subscribe(ctx, []string{"AAPL", "MSFT"}))
+	defer stream.Close()
+
+	var symbols []string
+	var errs []error
+	for data, err := range stream.All() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		symbols = append(symbols, data.Symbol)
+	}
+
+	require.Equal(t, []string{"AAPL", "MSFT"}, symbols)
+	require.Len(t, errs, 2)
+	require.True(t, IsRecoverable(errs[0]))
+	require.ErrorIs(t, errs[1], ErrStreamEnded)
+}
+
+func TestLivePriceStreamAllBreak(t *testing.T) {
+	srv := newFiniteSSEServer(t, `{"s":"AAPL","p":1}`, `{"s":"MSFT","p":2}`)
+	client := newOfflineTestClient(t, srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := client.GetLivePriceStreamForUS()
+	require.NoError(t, stream.Subscribe(ctx, []string{"AAPL", "MSFT"}))
+	defer stream.Close()
+
+	var symbols []string
+	for data, err := range stream.All() {
+		require.NoError(t, err)
+		symbols = append(symbols, data.Symbol)
+		break
+	}
+	require.Equal(t, []string{"AAPL"}, symbols)
+}
+
+func TestNewsStreamAll(t *testing.T) {
+	srv := newFiniteSSEServer(t, `[{"id":"1"},{"id":"2"}]`, `not json`, `[{"id":"3"}]`)
+	client := newOfflineTestClient(t, srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := client.GetNewsStream(StreamNewsParams{Region: RegionUs, Locale: LocaleEn})
+	stream.SetReconnect(false, 0)
+	require.NoError(t, stream.Subscribe(ctx))
+	defer stream.Close()
+
+	var ids []string
+	var errs []error
+	for news, err := range stream.All() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, news.ID)
+	}
+
+	require.Equal(t, []string{"1", "2", "3"}, ids)
+	require.Len(t, errs, 2)
+	require.True(t, IsRecoverable(errs[0]))
+	require.ErrorIs(t, errs[1], ErrStreamEnded)
+}
+
+func TestNewsStreamAllBreak(t *testing.T) {
+	srv := newFiniteSSEServer(t, `[{"id":"1"},{"id":"2"}]`)
+	client := newOfflineTestClient(t, srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream := client.GetNewsStream(StreamNewsParams{Region: RegionUs, Locale: LocaleEn})
+	require.NoError(t, stream.Subscribe(ctx))
+	defer stream.Close()
+
+	var ids []string
+	for news, err := range stream.All() {
+		require.NoError(t, err)
+		ids = append(ids, news.ID)
+		break
+	}
+	require.Equal(t, []string{"1"}, ids)
+}